@@ -1,3 +1,8 @@
 module github.com/aisbergg/go-jsonpointer
 
-go 1.16
+go 1.20
+
+require (
+	golang.org/x/text v0.14.0
+	google.golang.org/protobuf v1.34.2
+)
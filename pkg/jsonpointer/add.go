@@ -0,0 +1,137 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Add adds the value to the document at the location that the pointer points
+// to. See Resolver.Add.
+func (p Pointer) Add(doc interface{}, value interface{}) error {
+	return defaultResolver.Add(p, doc, value)
+}
+
+// Add adds the value to the document at the location that the pointer points
+// to, following the semantics of the JSON Patch "add" operation (RFC 6902).
+// Unlike Set, the target does not need to exist: a value is inserted into a
+// slice at the index, moving the following elements up, and appended if the
+// token is "-" or equal to the length of the slice. Map keys are created or
+// replaced and struct fields are replaced. The document root cannot be
+// replaced.
+func (r *Resolver) Add(p Pointer, doc interface{}, value interface{}) error {
+	if len(p) == 0 {
+		return newError(ErrSet, "cannot add to the document root")
+	}
+	return r.add(reflect.ValueOf(doc), p, value)
+}
+
+func (r *Resolver) add(docVal reflect.Value, p Pointer, value interface{}) error {
+	if err := r.checkMutable(docVal); err != nil {
+		return err
+	}
+	if len(p) == 1 {
+		return r.addValue(docVal, p[0], value)
+	}
+
+	childVal, err := r.getValue(docVal, p[0])
+	if err != nil {
+		return err
+	}
+
+	// modify a copy of an unaddressable element and store it back
+	elemVal := childVal
+	if elemVal.Kind() == reflect.Interface {
+		elemVal = elemVal.Elem()
+	}
+	switch elemVal.Kind() {
+	case reflect.Struct, reflect.Array, reflect.Slice:
+		if elemVal.CanSet() {
+			break
+		}
+		tmpVal := reflect.New(elemVal.Type()).Elem()
+		tmpVal.Set(elemVal)
+		if err := r.add(tmpVal, p[1:], value); err != nil {
+			return err
+		}
+		if childVal.CanSet() {
+			childVal.Set(tmpVal)
+			return nil
+		}
+		if mapVal := indirect(docVal); mapVal.Kind() == reflect.Map {
+			keyVal, err := mapKey(mapVal, p[0])
+			if err != nil {
+				return err
+			}
+			mapVal.SetMapIndex(keyVal, tmpVal)
+			return nil
+		}
+		return newError(ErrSet, "cannot add to unaddressable value of type %s", elemVal.Type())
+	}
+
+	return r.add(childVal, p[1:], value)
+}
+
+// addValue adds the value to the container at the location addressed by the
+// key.
+func (r *Resolver) addValue(doc reflect.Value, key string, value interface{}) error {
+	doc = indirect(doc)
+	switch doc.Kind() {
+	case reflect.Map:
+		keyVal, err := mapKey(doc, key)
+		if err != nil {
+			return err
+		}
+		if doc.IsNil() {
+			return newError(ErrSet, "cannot add to nil map")
+		}
+		elem := reflect.New(doc.Type().Elem()).Elem()
+		if err := r.setValue(elem, value); err != nil {
+			return err
+		}
+		doc.SetMapIndex(keyVal, elem)
+		return nil
+
+	case reflect.Slice:
+		i := doc.Len()
+		if key != "-" {
+			var err error
+			if i, err = strconv.Atoi(key); err != nil {
+				return newError(ErrGet, "invalid array index: %s", key)
+			}
+			if i < 0 || i > doc.Len() {
+				return newError(ErrIndexOutOfRange, "index %d exceeds array length of %d", i, doc.Len())
+			}
+		}
+		if !doc.CanSet() {
+			return newError(ErrSet, "cannot add to unaddressable slice")
+		}
+		elem := reflect.New(doc.Type().Elem()).Elem()
+		if err := r.setValue(elem, value); err != nil {
+			return err
+		}
+		newVal := reflect.MakeSlice(doc.Type(), doc.Len()+1, doc.Len()+1)
+		reflect.Copy(newVal, doc.Slice(0, i))
+		newVal.Index(i).Set(elem)
+		reflect.Copy(newVal.Slice(i+1, newVal.Len()), doc.Slice(i, doc.Len()))
+		doc.Set(newVal)
+		return nil
+
+	case reflect.Struct:
+		sf, ok := findField(doc.Type(), key, r.tagName())
+		if !ok {
+			return newError(ErrGet, "struct has no field '%s'", key)
+		}
+		f, err := doc.FieldByIndexErr(sf.Index)
+		if err != nil {
+			return newError(ErrGet, "document value is nil")
+		}
+		if !f.CanSet() {
+			return newError(ErrSet, "cannot add to unaddressable struct field '%s'", key)
+		}
+		return r.setValue(f, value)
+
+	case reflect.Invalid:
+		return newError(ErrGet, "document value is invalid")
+	}
+	return newError(ErrSet, "cannot add token '%s' to value of type %s", key, doc.Type())
+}
@@ -0,0 +1,78 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	// examples from RFC 6902, appendix A
+	cases := []struct {
+		name   string
+		doc    string
+		path   string
+		value  interface{}
+		expect string
+		err    string
+	}{
+		{"add object member", `{"foo":"bar"}`, "/baz", "qux", `{"baz":"qux","foo":"bar"}`, ""},
+		{"add array element", `{"foo":["bar","baz"]}`, "/foo/1", "qux", `{"foo":["bar","qux","baz"]}`, ""},
+		{"add nested member object", `{"foo":"bar"}`, "/child", map[string]interface{}{"grandchild": map[string]interface{}{}}, `{"child":{"grandchild":{}},"foo":"bar"}`, ""},
+		{"add array value", `{"foo":["bar"]}`, "/foo/-", []interface{}{"abc", "def"}, `{"foo":["bar",["abc","def"]]}`, ""},
+		{"replace existing member", `{"foo":"bar"}`, "/foo", "baz", `{"foo":"baz"}`, ""},
+		{"insert at start", `{"foo":["bar"]}`, "/foo/0", "qux", `{"foo":["qux","bar"]}`, ""},
+		{"append at length", `{"foo":["bar","baz"]}`, "/foo/2", "qux", `{"foo":["bar","baz","qux"]}`, ""},
+		{"add to nested array", `{"foo":[{"bar":[1]}]}`, "/foo/0/bar/-", 2, `{"foo":[{"bar":[1,2]}]}`, ""},
+		{"index exceeds length", `{"foo":["bar","baz"]}`, "/foo/3", "qux", "", "get: index 3 exceeds array length of 2"},
+		{"invalid index", `{"foo":["bar"]}`, "/foo/bar", "qux", "", "get: invalid array index: bar"},
+		{"nonexistent parent", `{"foo":"bar"}`, "/baz/bat", "qux", "", "get: map has no key 'baz'"},
+		{"document root", `{"foo":"bar"}`, "", "qux", "", "set: cannot add to the document root"},
+	}
+
+	for _, c := range cases {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(c.doc), &doc); err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		ptr, _ := New(c.path)
+		err := ptr.Add(doc, c.value)
+		if assertError(t, c.name, err, c.err) {
+			continue
+		}
+		got, _ := json.Marshal(doc)
+		if string(got) != c.expect {
+			t.Errorf("%s: value mismatch, expected: %s, got: %s", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestAddStruct(t *testing.T) {
+	type Doc struct {
+		Name  string   `json:"name"`
+		Items []string `json:"items"`
+	}
+	doc := &Doc{Name: "foo", Items: []string{"a", "c"}}
+
+	for _, c := range []struct {
+		path  string
+		value interface{}
+	}{
+		{"/name", "bar"},
+		{"/items/1", "b"},
+		{"/items/-", "d"},
+	} {
+		ptr, _ := New(c.path)
+		if err := ptr.Add(doc, c.value); err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.path, err)
+		}
+	}
+
+	expect := &Doc{Name: "bar", Items: []string{"a", "b", "c", "d"}}
+	if !reflect.DeepEqual(doc, expect) {
+		t.Errorf("value mismatch, expected: %#v, got: %#v", expect, doc)
+	}
+
+	err := Pointer{"missing"}.Add(doc, 1)
+	assertError(t, "/missing", err, "get: struct has no field 'missing'")
+}
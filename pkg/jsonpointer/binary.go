@@ -0,0 +1,148 @@
+package jsonpointer
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// AppendBinary appends the binary encoding of the pointer to b and returns the
+// extended buffer. The pointer is encoded as the number of tokens followed by
+// each token prefixed with its length, all lengths being unsigned varints.
+// Encoded pointers can be packed back-to-back and decoded with DecodeBinary.
+func (p Pointer) AppendBinary(b []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(p)))
+	for _, tok := range p {
+		b = binary.AppendUvarint(b, uint64(len(tok)))
+		b = append(b, tok...)
+	}
+	return b
+}
+
+// DecodeBinary decodes a pointer that was encoded with AppendBinary from the
+// beginning of b. It returns the pointer and the number of bytes read.
+func DecodeBinary(b []byte) (Pointer, int, error) {
+	numToks, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, 0, newError(ErrInvalidJSONPointer, "invalid binary encoding: malformed token count")
+	}
+	// each token takes at least one byte
+	if numToks > uint64(len(b)-n) {
+		return nil, 0, newError(ErrInvalidJSONPointer, "invalid binary encoding: token count %d exceeds data", numToks)
+	}
+
+	newPtr := make(Pointer, 0, numToks)
+	for i := uint64(0); i < numToks; i++ {
+		tokLen, m := binary.Uvarint(b[n:])
+		if m <= 0 {
+			return nil, 0, newError(ErrInvalidJSONPointer, "invalid binary encoding: malformed length of token %d", i)
+		}
+		n += m
+		if tokLen > uint64(len(b)-n) {
+			return nil, 0, newError(ErrInvalidJSONPointer, "invalid binary encoding: token %d is truncated", i)
+		}
+		newPtr = append(newPtr, string(b[n:n+int(tokLen)]))
+		n += int(tokLen)
+	}
+	return newPtr, n, nil
+}
+
+// trieNode is a node of a prefix trie of pointer tokens.
+type trieNode struct {
+	terminal bool
+	toks     []string
+	children map[string]*trieNode
+}
+
+func (n *trieNode) insert(p Pointer) {
+	for _, tok := range p {
+		child, ok := n.children[tok]
+		if !ok {
+			if n.children == nil {
+				n.children = map[string]*trieNode{}
+			}
+			child = &trieNode{}
+			n.children[tok] = child
+			n.toks = append(n.toks, tok)
+		}
+		n = child
+	}
+	n.terminal = true
+}
+
+func (n *trieNode) appendBinary(b []byte) []byte {
+	if n.terminal {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	sort.Strings(n.toks)
+	b = binary.AppendUvarint(b, uint64(len(n.toks)))
+	for _, tok := range n.toks {
+		b = binary.AppendUvarint(b, uint64(len(tok)))
+		b = append(b, tok...)
+		b = n.children[tok].appendBinary(b)
+	}
+	return b
+}
+
+// EncodeTrie encodes a set of pointers as a prefix trie of their tokens, so
+// that common prefixes are stored only once. Duplicate pointers are encoded
+// only once.
+func EncodeTrie(ptrs []Pointer) []byte {
+	root := &trieNode{}
+	for _, ptr := range ptrs {
+		root.insert(ptr)
+	}
+	return root.appendBinary(nil)
+}
+
+// DecodeTrie decodes a set of pointers that was encoded with EncodeTrie. The
+// pointers are returned in sorted order.
+func DecodeTrie(b []byte) ([]Pointer, error) {
+	var ptrs []Pointer
+	n, err := decodeTrieNode(b, Pointer{}, &ptrs)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(b) {
+		return nil, newError(ErrInvalidJSONPointer, "invalid trie encoding: %d trailing bytes", len(b)-n)
+	}
+	return ptrs, nil
+}
+
+func decodeTrieNode(b []byte, prefix Pointer, ptrs *[]Pointer) (int, error) {
+	if len(b) == 0 || b[0] > 1 {
+		return 0, newError(ErrInvalidJSONPointer, "invalid trie encoding: malformed node")
+	}
+	if b[0] == 1 {
+		newPtr := make(Pointer, len(prefix))
+		copy(newPtr, prefix)
+		*ptrs = append(*ptrs, newPtr)
+	}
+	n := 1
+
+	numChildren, m := binary.Uvarint(b[n:])
+	if m <= 0 {
+		return 0, newError(ErrInvalidJSONPointer, "invalid trie encoding: malformed child count")
+	}
+	n += m
+	for i := uint64(0); i < numChildren; i++ {
+		tokLen, m := binary.Uvarint(b[n:])
+		if m <= 0 {
+			return 0, newError(ErrInvalidJSONPointer, "invalid trie encoding: malformed token length")
+		}
+		n += m
+		if tokLen > uint64(len(b)-n) {
+			return 0, newError(ErrInvalidJSONPointer, "invalid trie encoding: token is truncated")
+		}
+		tok := string(b[n : n+int(tokLen)])
+		n += int(tokLen)
+
+		m, err := decodeTrieNode(b[n:], append(prefix[:len(prefix):len(prefix)], tok), ptrs)
+		if err != nil {
+			return 0, err
+		}
+		n += m
+	}
+	return n, nil
+}
@@ -0,0 +1,149 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	ptrs := []Pointer{
+		{},
+		{""},
+		{"foo", "bar", "0"},
+		{"a/b", "", "m~n"},
+		{"", "", ""},
+		{"ünïcödé", "\x00"},
+	}
+
+	// pack all pointers back-to-back
+	var b []byte
+	for _, ptr := range ptrs {
+		b = ptr.AppendBinary(b)
+	}
+
+	for i, expect := range ptrs {
+		got, n, err := DecodeBinary(b)
+		if err != nil {
+			t.Fatalf("case %d: expected no error, got: %s", i, err)
+		}
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("case %d: expected: %#v, got: %#v", i, expect, got)
+		}
+		b = b[n:]
+	}
+	if len(b) != 0 {
+		t.Errorf("expected all bytes to be consumed, %d left", len(b))
+	}
+}
+
+func TestDecodeBinaryInvalid(t *testing.T) {
+	valid := Pointer{"foo", "bar"}.AppendBinary(nil)
+
+	cases := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"empty", []byte{}, "invalid pointer: invalid binary encoding: malformed token count"},
+		{"truncated token", valid[:len(valid)-1], "invalid pointer: invalid binary encoding: token 1 is truncated"},
+		{"missing token", valid[:5], "invalid pointer: invalid binary encoding: malformed length of token 1"},
+		{"too many tokens", []byte{0x05, 0x00}, "invalid pointer: invalid binary encoding: token count 5 exceeds data"},
+	}
+
+	for _, c := range cases {
+		_, _, err := DecodeBinary(c.data)
+		assertError(t, c.name, err, c.err)
+	}
+}
+
+func TestTrieRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		ptrs []Pointer
+	}{
+		{"empty", nil},
+		{"root", []Pointer{{}}},
+		{"overlapping", []Pointer{
+			{"config", "db", "host"},
+			{"config", "db", "port"},
+			{"config", "db"},
+			{"config", "cache", "ttl"},
+			{"config", "cache", "size"},
+		}},
+		{"disjoint", []Pointer{
+			{"foo", "0"},
+			{"bar"},
+			{"", "a/b", "m~n"},
+		}},
+	}
+
+	for _, c := range cases {
+		got, err := DecodeTrie(EncodeTrie(c.ptrs))
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.name, err)
+			continue
+		}
+		if !samePointerSet(got, c.ptrs) {
+			t.Errorf("%s: expected: %v, got: %v", c.name, c.ptrs, got)
+		}
+	}
+
+	// duplicates are only encoded once
+	got, _ := DecodeTrie(EncodeTrie([]Pointer{{"foo"}, {"foo"}}))
+	if !reflect.DeepEqual(got, []Pointer{{"foo"}}) {
+		t.Errorf("expected duplicates to be removed, got: %v", got)
+	}
+}
+
+func TestTrieSize(t *testing.T) {
+	var ptrs []Pointer
+	for _, service := range []string{"frontend", "backend", "database"} {
+		for _, key := range []string{"host", "port", "username", "password"} {
+			ptrs = append(ptrs, Pointer{"configuration", "services", service, key})
+		}
+	}
+
+	var naive []byte
+	for _, ptr := range ptrs {
+		naive = ptr.AppendBinary(naive)
+	}
+	trie := EncodeTrie(ptrs)
+	if len(trie) >= len(naive) {
+		t.Errorf("expected trie encoding (%d bytes) to be smaller than naive encoding (%d bytes)", len(trie), len(naive))
+	}
+}
+
+func TestDecodeTrieInvalid(t *testing.T) {
+	valid := EncodeTrie([]Pointer{{"foo", "bar"}})
+
+	cases := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"empty", []byte{}, "invalid pointer: invalid trie encoding: malformed node"},
+		{"truncated", valid[:len(valid)-3], "invalid pointer: invalid trie encoding: token is truncated"},
+		{"trailing", append(valid, 0), "invalid pointer: invalid trie encoding: 1 trailing bytes"},
+	}
+
+	for _, c := range cases {
+		_, err := DecodeTrie(c.data)
+		assertError(t, c.name, err, c.err)
+	}
+}
+
+func samePointerSet(a, b []Pointer) bool {
+	set := map[string]bool{}
+	for _, p := range a {
+		set[p.String()] = true
+	}
+	if len(set) != len(a) {
+		return false
+	}
+	for _, p := range b {
+		if !set[p.String()] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
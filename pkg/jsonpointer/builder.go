@@ -0,0 +1,40 @@
+package jsonpointer
+
+import "reflect"
+
+// GuidedBuilder builds a pointer token by token, validating each token against
+// a document. Tokens that cannot be resolved against the current value are
+// rejected, so that the built pointer always resolves against the document.
+type GuidedBuilder struct {
+	ptr Pointer
+	cur reflect.Value
+}
+
+// NewGuidedBuilder creates a new GuidedBuilder for the given document,
+// starting at the document root.
+func NewGuidedBuilder(doc interface{}) *GuidedBuilder {
+	return &GuidedBuilder{
+		ptr: Pointer{},
+		cur: reflect.ValueOf(doc),
+	}
+}
+
+// Descend resolves the token against the current value and appends it to the
+// pointer. If the token cannot be resolved, an error is returned and the
+// builder is left unchanged.
+func (b *GuidedBuilder) Descend(token string) error {
+	childVal, err := defaultResolver.getValue(b.cur, token)
+	if err != nil {
+		return err
+	}
+	b.ptr = childPointer(b.ptr, token)
+	b.cur = childVal
+	return nil
+}
+
+// Pointer returns the pointer built so far.
+func (b *GuidedBuilder) Pointer() Pointer {
+	newPtr := make(Pointer, len(b.ptr))
+	copy(newPtr, b.ptr)
+	return newPtr
+}
@@ -0,0 +1,47 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGuidedBuilder(t *testing.T) {
+	doc := map[string]interface{}{
+		"foo": []interface{}{
+			map[string]interface{}{"bar": "baz"},
+		},
+	}
+
+	cases := []struct {
+		token  string
+		expect string
+		err    string
+	}{
+		{"foo", "/foo", ""},
+		{"1", "/foo", "get: index 1 exceeds array length of 1"},
+		{"0", "/foo/0", ""},
+		{"qux", "/foo/0", "get: map has no key 'qux'"},
+		{"bar", "/foo/0/bar", ""},
+		{"x", "/foo/0/bar", "get: cannot resolve token 'x' in primitive value of type string"},
+	}
+
+	b := NewGuidedBuilder(doc)
+	if ptr := b.Pointer(); !ptr.IsEmpty() {
+		t.Errorf("expected an empty pointer, got: %s", ptr)
+	}
+	for _, c := range cases {
+		err := b.Descend(c.token)
+		assertError(t, c.token, err, c.err)
+		if got := b.Pointer().String(); got != c.expect {
+			t.Errorf("%s: expected pointer: '%s', got: '%s'", c.token, c.expect, got)
+		}
+	}
+
+	value, err := b.Pointer().Get(doc)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if !reflect.DeepEqual(value, "baz") {
+		t.Errorf("value mismatch, expected: %#v, got: %#v", "baz", value)
+	}
+}
@@ -0,0 +1,74 @@
+package jsonpointer
+
+import (
+	"sync"
+	"time"
+)
+
+// CachingResolver resolves pointers against a document and caches the
+// resolved values for a limited time. This is useful when resolution is
+// expensive, e.g. for documents with computed nodes (see Resolver.CallFuncs).
+// Values are cached by the canonical string of the pointer; errors are not
+// cached. It is safe for concurrent use.
+type CachingResolver struct {
+	resolver *Resolver
+	doc      interface{}
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewCachingResolver creates a new CachingResolver that resolves pointers
+// against doc with the given resolver and caches the values for ttl. If r is
+// nil, the default resolver is used.
+func NewCachingResolver(doc interface{}, r *Resolver, ttl time.Duration) *CachingResolver {
+	if r == nil {
+		r = defaultResolver
+	}
+	return &CachingResolver{
+		resolver: r,
+		doc:      doc,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]cacheEntry{},
+	}
+}
+
+// Get returns the value from the document that the pointer points to. If the
+// value was resolved before and has not yet expired, the cached value is
+// returned.
+func (c *CachingResolver) Get(p Pointer) (interface{}, error) {
+	key := p.String()
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.value, nil
+	}
+
+	value, err := c.resolver.Get(p, c.doc)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// Invalidate removes all cached values.
+func (c *CachingResolver) Invalidate() {
+	c.mu.Lock()
+	c.entries = map[string]cacheEntry{}
+	c.mu.Unlock()
+}
@@ -0,0 +1,58 @@
+package jsonpointer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachingResolver(t *testing.T) {
+	calls := 0
+	doc := map[string]interface{}{
+		"computed": func() interface{} {
+			calls++
+			return map[string]interface{}{"value": calls}
+		},
+	}
+
+	now := time.Date(2022, 7, 9, 12, 0, 0, 0, time.UTC)
+	c := NewCachingResolver(doc, &Resolver{CallFuncs: true}, time.Minute)
+	c.now = func() time.Time { return now }
+	ptr, _ := New("/computed/value")
+
+	assertGet := func(expectValue, expectCalls int) {
+		t.Helper()
+		got, err := c.Get(ptr)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		if got != expectValue {
+			t.Errorf("expected value %d, got: %#v", expectValue, got)
+		}
+		if calls != expectCalls {
+			t.Errorf("expected %d calls, got: %d", expectCalls, calls)
+		}
+	}
+
+	assertGet(1, 1)
+
+	// cached within TTL
+	now = now.Add(30 * time.Second)
+	assertGet(1, 1)
+
+	// recomputed after expiry
+	now = now.Add(31 * time.Second)
+	assertGet(2, 2)
+
+	// recomputed after invalidation
+	c.Invalidate()
+	assertGet(3, 3)
+
+	// errors are not cached
+	ptr, _ = New("/missing")
+	if _, err := c.Get(ptr); err == nil {
+		t.Errorf("expected an error")
+	}
+	if _, ok := c.entries["/missing"]; ok {
+		t.Errorf("expected error not to be cached")
+	}
+}
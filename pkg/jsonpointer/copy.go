@@ -0,0 +1,84 @@
+package jsonpointer
+
+import "reflect"
+
+// deepCopy returns a deep copy of the value. Maps, slices, arrays, pointers and
+// interfaces are copied recursively, as are the exported fields of structs.
+// Unexported fields are copied shallowly. Pointers, maps and slices that are
+// referenced multiple times are copied once, so that shared and cyclic
+// references are preserved in the copy.
+func deepCopy(val reflect.Value) reflect.Value {
+	return copyValue(val, map[nodeID]reflect.Value{})
+}
+
+// copyValue copies the value like deepCopy. The copies of pointers, maps and
+// slices are recorded by their identity before their contents are copied.
+func copyValue(val reflect.Value, copies map[nodeID]reflect.Value) reflect.Value {
+	if id, ok := nodeIdentity(val); ok && val.Kind() != reflect.Interface {
+		if cpy, ok := copies[id]; ok {
+			return cpy
+		}
+	}
+
+	switch val.Kind() {
+	case reflect.Interface:
+		if val.IsNil() {
+			return val
+		}
+		cpy := reflect.New(val.Type()).Elem()
+		cpy.Set(copyValue(val.Elem(), copies))
+		return cpy
+
+	case reflect.Pointer:
+		if val.IsNil() {
+			return val
+		}
+		cpy := reflect.New(val.Type().Elem())
+		copies[nodeID{val.Type(), val.Pointer(), 0}] = cpy
+		cpy.Elem().Set(copyValue(val.Elem(), copies))
+		return cpy
+
+	case reflect.Map:
+		if val.IsNil() {
+			return val
+		}
+		cpy := reflect.MakeMapWithSize(val.Type(), val.Len())
+		copies[nodeID{val.Type(), val.Pointer(), 0}] = cpy
+		iter := val.MapRange()
+		for iter.Next() {
+			cpy.SetMapIndex(iter.Key(), copyValue(iter.Value(), copies))
+		}
+		return cpy
+
+	case reflect.Slice:
+		if val.IsNil() {
+			return val
+		}
+		cpy := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+		if val.Len() > 0 {
+			copies[nodeID{val.Type(), val.Pointer(), val.Len()}] = cpy
+		}
+		for i := 0; i < val.Len(); i++ {
+			cpy.Index(i).Set(copyValue(val.Index(i), copies))
+		}
+		return cpy
+
+	case reflect.Array:
+		cpy := reflect.New(val.Type()).Elem()
+		for i := 0; i < val.Len(); i++ {
+			cpy.Index(i).Set(copyValue(val.Index(i), copies))
+		}
+		return cpy
+
+	case reflect.Struct:
+		cpy := reflect.New(val.Type()).Elem()
+		cpy.Set(val)
+		for i := 0; i < val.NumField(); i++ {
+			if f := cpy.Field(i); f.CanSet() {
+				f.Set(copyValue(val.Field(i), copies))
+			}
+		}
+		return cpy
+	}
+	return val
+}
@@ -0,0 +1,87 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeepCopy(t *testing.T) {
+	type Inner struct {
+		Tags   []string
+		hidden []string
+	}
+	hidden := []string{"x"}
+	inner := &Inner{Tags: []string{"a"}, hidden: hidden}
+	src := map[string]interface{}{
+		"list":  []interface{}{map[string]interface{}{"a": 1}},
+		"inner": inner,
+		"array": [1][]int{{1}},
+		"nil":   nil,
+	}
+
+	cpy := deepCopy(reflect.ValueOf(src)).Interface().(map[string]interface{})
+	if !reflect.DeepEqual(cpy, src) {
+		t.Fatalf("value mismatch, expected: %#v, got: %#v", src, cpy)
+	}
+
+	cpy["list"].([]interface{})[0].(map[string]interface{})["a"] = 2
+	cpy["inner"].(*Inner).Tags[0] = "b"
+	cpy["array"].([1][]int)[0][0] = 2
+	cpy["new"] = true
+
+	expect := map[string]interface{}{
+		"list":  []interface{}{map[string]interface{}{"a": 1}},
+		"inner": &Inner{Tags: []string{"a"}, hidden: hidden},
+		"array": [1][]int{{1}},
+		"nil":   nil,
+	}
+	if !reflect.DeepEqual(src, expect) {
+		t.Errorf("source was modified, expected: %#v, got: %#v", expect, src)
+	}
+	if cpy["inner"] == src["inner"] {
+		t.Errorf("expected pointers to be copied")
+	}
+}
+
+func TestDeepCopyCycles(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+	node := &Node{Name: "a"}
+	node.Next = &Node{Name: "b", Next: node}
+
+	shared := map[string]interface{}{"x": 1}
+	src := map[string]interface{}{"node": node, "a": shared, "b": shared}
+	src["self"] = src
+
+	cpy := deepCopy(reflect.ValueOf(src)).Interface().(map[string]interface{})
+	if reflect.ValueOf(cpy["self"]).Pointer() != reflect.ValueOf(cpy).Pointer() {
+		t.Errorf("expected the cyclic map to refer to its copy")
+	}
+	cpyNode := cpy["node"].(*Node)
+	if cpyNode == node || cpyNode.Next.Next != cpyNode || cpyNode.Next.Name != "b" {
+		t.Errorf("expected the cyclic pointers to refer to their copies")
+	}
+	cpy["a"].(map[string]interface{})["x"] = 2
+	if cpy["b"].(map[string]interface{})["x"] != 2 || shared["x"] != 1 {
+		t.Errorf("expected shared maps to be copied once")
+	}
+
+	list := []interface{}{nil}
+	list[0] = list
+	cpyList := deepCopy(reflect.ValueOf(list)).Interface().([]interface{})
+	if reflect.ValueOf(cpyList[0]).Pointer() != reflect.ValueOf(cpyList).Pointer() {
+		t.Errorf("expected the cyclic slice to refer to its copy")
+	}
+
+	// values of cyclic documents can be copied on get
+	r := Resolver{CopyOnGet: true}
+	got, err := r.Get(Pointer{"self", "node"}, src)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if got.(*Node) == node || got.(*Node).Next.Next != got.(*Node) {
+		t.Errorf("expected a copy of the cyclic value, got: %#v", got)
+	}
+}
@@ -0,0 +1,59 @@
+package jsonpointer
+
+import "reflect"
+
+// Cursor navigates a document step by step. It keeps a stack of the resolved
+// values, so that related values can be looked up without resolving them from
+// the document root again.
+type Cursor struct {
+	ptr   Pointer
+	stack []reflect.Value
+}
+
+// NewCursor creates a new Cursor for the given document, positioned at the
+// document root.
+func NewCursor(doc interface{}) *Cursor {
+	return &Cursor{
+		ptr:   Pointer{},
+		stack: []reflect.Value{reflect.ValueOf(doc)},
+	}
+}
+
+// Enter resolves the token against the current value and moves the cursor to
+// the resolved child. If the token cannot be resolved, an error is returned
+// and the cursor is left unchanged.
+func (c *Cursor) Enter(token string) error {
+	childVal, err := defaultResolver.getValue(c.stack[len(c.stack)-1], token)
+	if err != nil {
+		return err
+	}
+	c.ptr = childPointer(c.ptr, token)
+	c.stack = append(c.stack, childVal)
+	return nil
+}
+
+// Leave moves the cursor back to the parent of the current value. At the
+// document root, it does nothing.
+func (c *Cursor) Leave() {
+	if len(c.ptr) == 0 {
+		return
+	}
+	c.ptr = c.ptr[:len(c.ptr)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+// Value returns the current value.
+func (c *Cursor) Value() interface{} {
+	curVal := c.stack[len(c.stack)-1]
+	if !curVal.IsValid() || !curVal.CanInterface() {
+		return nil
+	}
+	return curVal.Interface()
+}
+
+// Pointer returns the pointer to the current value.
+func (c *Cursor) Pointer() Pointer {
+	newPtr := make(Pointer, len(c.ptr))
+	copy(newPtr, c.ptr)
+	return newPtr
+}
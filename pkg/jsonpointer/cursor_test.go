@@ -0,0 +1,46 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	bar := []interface{}{"a", "b"}
+	foo := map[string]interface{}{"bar": bar}
+	doc := map[string]interface{}{"foo": foo}
+
+	c := NewCursor(doc)
+	steps := []struct {
+		enter  string
+		leave  bool
+		expect interface{}
+		ptr    string
+		err    string
+	}{
+		{enter: "foo", expect: foo, ptr: "/foo"},
+		{enter: "bar", expect: bar, ptr: "/foo/bar"},
+		{enter: "1", expect: "b", ptr: "/foo/bar/1"},
+		{leave: true, expect: bar, ptr: "/foo/bar"},
+		{enter: "2", expect: bar, ptr: "/foo/bar", err: "get: index 2 exceeds array length of 2"},
+		{enter: "0", expect: "a", ptr: "/foo/bar/0"},
+		{leave: true, expect: bar, ptr: "/foo/bar"},
+		{leave: true, expect: foo, ptr: "/foo"},
+		{leave: true, expect: doc, ptr: ""},
+		{leave: true, expect: doc, ptr: ""},
+	}
+
+	for i, s := range steps {
+		if s.leave {
+			c.Leave()
+		} else {
+			assertError(t, s.enter, c.Enter(s.enter), s.err)
+		}
+		if got := c.Value(); !reflect.DeepEqual(got, s.expect) {
+			t.Errorf("%d: value mismatch, expected: %#v, got: %#v", i, s.expect, got)
+		}
+		if got := c.Pointer().String(); got != s.ptr {
+			t.Errorf("%d: expected pointer: '%s', got: '%s'", i, s.ptr, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Delete removes the value that the pointer points to from the document. See
+// Resolver.Delete.
+func (p Pointer) Delete(doc interface{}) error {
+	return defaultResolver.Delete(p, doc)
+}
+
+// Delete removes the value that the pointer points to from the document. Map
+// keys are removed, struct fields are set to their zero value and slice
+// elements are removed by moving the following elements down. Deleting a
+// non-existent map key is a no-op. The document root cannot be deleted.
+func (r *Resolver) Delete(p Pointer, doc interface{}) error {
+	if len(p) == 0 {
+		return newError(ErrSet, "cannot delete the document root")
+	}
+	return r.delete(reflect.ValueOf(doc), p)
+}
+
+func (r *Resolver) delete(docVal reflect.Value, p Pointer) error {
+	if err := r.checkMutable(docVal); err != nil {
+		return err
+	}
+	if len(p) == 1 {
+		return deleteValue(docVal, p[0], r.tagName())
+	}
+
+	childVal, err := r.getValue(docVal, p[0])
+	if err != nil {
+		return err
+	}
+
+	// modify a copy of an unaddressable element and store it back
+	elemVal := childVal
+	if elemVal.Kind() == reflect.Interface {
+		elemVal = elemVal.Elem()
+	}
+	switch elemVal.Kind() {
+	case reflect.Struct, reflect.Array, reflect.Slice:
+		if elemVal.CanSet() {
+			break
+		}
+		tmpVal := reflect.New(elemVal.Type()).Elem()
+		tmpVal.Set(elemVal)
+		if err := r.delete(tmpVal, p[1:]); err != nil {
+			return err
+		}
+		if childVal.CanSet() {
+			childVal.Set(tmpVal)
+			return nil
+		}
+		if mapVal := indirect(docVal); mapVal.Kind() == reflect.Map {
+			keyVal, err := mapKey(mapVal, p[0])
+			if err != nil {
+				return err
+			}
+			mapVal.SetMapIndex(keyVal, tmpVal)
+			return nil
+		}
+		return newError(ErrSet, "cannot delete from unaddressable value of type %s", elemVal.Type())
+	}
+
+	return r.delete(childVal, p[1:])
+}
+
+// deleteValue removes the element addressed by the key from the container.
+// Struct fields are matched by their tag name for the given tag.
+func deleteValue(doc reflect.Value, key, tag string) error {
+	doc = indirect(doc)
+	switch doc.Kind() {
+	case reflect.Map:
+		keyVal, err := mapKey(doc, key)
+		if err != nil {
+			return err
+		}
+		doc.SetMapIndex(keyVal, reflect.Value{})
+		return nil
+
+	case reflect.Slice:
+		i, err := strconv.Atoi(key)
+		if err != nil {
+			return newError(ErrGet, "invalid array index: %s", key)
+		}
+		if i < 0 || i >= doc.Len() {
+			return newError(ErrIndexOutOfRange, "index %d exceeds array length of %d", i, doc.Len())
+		}
+		if !doc.CanSet() {
+			return newError(ErrSet, "cannot delete from unaddressable slice")
+		}
+		doc.Set(reflect.AppendSlice(doc.Slice(0, i), doc.Slice(i+1, doc.Len())))
+		return nil
+
+	case reflect.Struct:
+		sf, ok := findField(doc.Type(), key, tag)
+		if !ok {
+			return newError(ErrGet, "struct has no field '%s'", key)
+		}
+		f, err := doc.FieldByIndexErr(sf.Index)
+		if err != nil {
+			return newError(ErrGet, "document value is nil")
+		}
+		if !f.CanSet() {
+			return newError(ErrSet, "cannot delete unaddressable struct field '%s'", key)
+		}
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+
+	case reflect.Invalid:
+		return newError(ErrGet, "document value is invalid")
+	}
+	return newError(ErrSet, "cannot delete token '%s' from value of type %s", key, doc.Type())
+}
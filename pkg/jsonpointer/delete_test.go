@@ -0,0 +1,62 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	type Inner struct {
+		Name  string `json:"name"`
+		Items []int  `json:"items"`
+	}
+	newDoc := func() map[string]interface{} {
+		return map[string]interface{}{
+			"foo":    map[string]interface{}{"bar": 1, "baz": 2},
+			"arr":    []interface{}{"a", "b", "c"},
+			"ints":   []int{1, 2, 3},
+			"struct": Inner{Name: "x", Items: []int{1, 2}},
+			"ptr":    &Inner{Name: "y"},
+			"fixed":  [2]int{1, 2},
+		}
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		errType   ErrType
+		err       string
+	}{
+		{"/foo/bar", map[string]interface{}{"baz": 2}, ErrUnknown, ""},
+		{"/foo/missing", map[string]interface{}{"bar": 1, "baz": 2}, ErrUnknown, ""},
+		{"/arr/0", []interface{}{"b", "c"}, ErrUnknown, ""},
+		{"/arr/1", []interface{}{"a", "c"}, ErrUnknown, ""},
+		{"/arr/2", []interface{}{"a", "b"}, ErrUnknown, ""},
+		{"/ints/1", []int{1, 3}, ErrUnknown, ""},
+		{"/struct/name", Inner{Items: []int{1, 2}}, ErrUnknown, ""},
+		{"/struct/items/0", Inner{Name: "x", Items: []int{2}}, ErrUnknown, ""},
+		{"/ptr/name", &Inner{}, ErrUnknown, ""},
+		{"", nil, ErrSet, "set: cannot delete the document root"},
+		{"/arr/3", nil, ErrIndexOutOfRange, "get: index 3 exceeds array length of 3"},
+		{"/arr/x", nil, ErrGet, "get: invalid array index: x"},
+		{"/missing/x", nil, ErrGet, "get: map has no key 'missing'"},
+		{"/struct/missing", nil, ErrGet, "get: struct has no field 'missing'"},
+		{"/fixed/0", nil, ErrSet, "set: cannot delete token '0' from value of type [2]int"},
+	}
+
+	for _, c := range cases {
+		doc := newDoc()
+		ptr, _ := New(c.ptrstring)
+		err := ptr.Delete(doc)
+		if err != nil && errType(err) != c.errType {
+			t.Errorf("%s: expected error type %v, got: %v", c.ptrstring, c.errType, errType(err))
+		}
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		got, _ := ptr.Truncate(1).Get(doc)
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
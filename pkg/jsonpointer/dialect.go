@@ -0,0 +1,82 @@
+package jsonpointer
+
+import (
+	"net/url"
+)
+
+// Dialect controls how pointer strings are parsed. The zero value parses
+// pointers according to RFC 6901, just like New does.
+type Dialect struct {
+	// CollapseEmptyTokens drops empty tokens while parsing, so that "//foo" is
+	// parsed as "/foo". This is not RFC 6901 compliant, as empty tokens
+	// address the empty-string key of an object.
+	CollapseEmptyTokens bool
+
+	// Decode converts a token from the character encoding of the pointer
+	// strings to UTF-8. If set, it is called for each token while parsing, so
+	// that the tokens match the keys of a document. By default, tokens are
+	// expected to be UTF-8 already. See the textenc package for decoders of
+	// golang.org/x/text encodings.
+	Decode func([]byte) ([]byte, error)
+
+	// MaxTokenLength limits the length of each token in bytes, measured in
+	// its escaped form. Pointers with longer tokens are rejected. Zero means
+	// no limit.
+	MaxTokenLength int
+
+	// ValidateUTF8 rejects pointers with tokens that are not valid UTF-8
+	// after decoding. As JSON strings must be valid UTF-8, such tokens cannot
+	// address a value of a JSON document.
+	ValidateUTF8 bool
+
+	strict bool
+}
+
+var (
+	defaultDialect = &Dialect{}
+	strictDialect  = &Dialect{strict: true}
+)
+
+// New creates a new JSON pointer from string, *url.URL or another Pointer, just
+// like the package-level New, but parses strings according to the dialect.
+func (d *Dialect) New(val interface{}) (Pointer, error) {
+	switch v := val.(type) {
+	case Pointer:
+		newPtr := make([]string, len(v))
+		copy(newPtr, v)
+		return newPtr, nil
+
+	case string:
+		// fast paths that skip url parse step
+		if len(v) == 0 || v == "#" {
+			return Pointer{}, nil
+		} else if v[0] == '/' {
+			return d.parse(v)
+		}
+
+		u, err := url.Parse(v)
+		if err != nil {
+			uerr := err.(*url.Error)
+			return nil, wrapError(uerr.Err, ErrInvalidJSONPointer, "failed to parse URL: %s", uerr.Err)
+		}
+		return d.parse(u.Fragment)
+
+	case *url.URL:
+		return d.parse(v.Fragment)
+
+	default:
+		return nil, newError(ErrInvalidJSONPointer, "invalid value for pointer: %T", v)
+	}
+}
+
+// decodeToken converts the token from the dialect's encoding to UTF-8.
+func (d *Dialect) decodeToken(tok string) (string, error) {
+	if d.Decode == nil {
+		return tok, nil
+	}
+	decTok, err := d.Decode([]byte(tok))
+	if err != nil {
+		return "", wrapError(err, ErrInvalidJSONPointer, "failed to decode token '%s': %s", tok, err)
+	}
+	return string(decTok), nil
+}
@@ -0,0 +1,135 @@
+package jsonpointer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDialectCollapseEmptyTokens(t *testing.T) {
+	cases := []struct {
+		raw      string
+		collapse bool
+		expect   Pointer
+	}{
+		{"//foo", false, Pointer{"", "foo"}},
+		{"//foo", true, Pointer{"foo"}},
+		{"/foo//bar/", false, Pointer{"foo", "", "bar", ""}},
+		{"/foo//bar/", true, Pointer{"foo", "bar"}},
+		{"#//foo", true, Pointer{"foo"}},
+		{"/", false, Pointer{""}},
+		{"/", true, Pointer{}},
+	}
+
+	for _, c := range cases {
+		d := Dialect{CollapseEmptyTokens: c.collapse}
+		got, err := d.New(c.raw)
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.raw, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s (collapse=%t): expected: %#v, got: %#v", c.raw, c.collapse, c.expect, got)
+		}
+	}
+
+	// the empty-string key is still addressable in strict mode
+	doc := map[string]interface{}{"": map[string]interface{}{"foo": 1}, "foo": 2}
+	strict, _ := New("//foo")
+	collapsed, _ := (&Dialect{CollapseEmptyTokens: true}).New("//foo")
+	if got, _ := strict.Get(doc); got != 1 {
+		t.Errorf("expected value 1, got: %#v", got)
+	}
+	if got, _ := collapsed.Get(doc); got != 2 {
+		t.Errorf("expected value 2, got: %#v", got)
+	}
+}
+
+// decodeLatin1 converts ISO 8859-1 encoded text to UTF-8.
+func decodeLatin1(data []byte) ([]byte, error) {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes)), nil
+}
+
+func TestDialectEncoding(t *testing.T) {
+	doc := map[string]interface{}{
+		"café": map[string]interface{}{"crème/brûlée": 1},
+	}
+	// "/café/crème~1brûlée" encoded as Latin-1
+	raw := "/caf\xe9/cr\xe8me~1br\xfbl\xe9e"
+
+	ptr, err := New(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if _, err := ptr.Get(doc); err == nil {
+		t.Errorf("expected undecoded token not to match the map key")
+	}
+
+	d := Dialect{Decode: decodeLatin1}
+	ptr, err = d.New(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if expect := (Pointer{"café", "crème/brûlée"}); !reflect.DeepEqual(ptr, expect) {
+		t.Errorf("expected: %#v, got: %#v", expect, ptr)
+	}
+	if got, err := ptr.Get(doc); err != nil || got != 1 {
+		t.Errorf("expected value 1, got: %#v (%v)", got, err)
+	}
+
+	// decoding errors are reported as parse errors
+	d = Dialect{Decode: func([]byte) ([]byte, error) { return nil, errors.New("bad input") }}
+	_, err = d.New("/foo")
+	assertError(t, "decode", err, "invalid pointer: failed to decode token 'foo': bad input")
+}
+
+func TestDialectMaxTokenLength(t *testing.T) {
+	cases := []struct {
+		raw string
+		max int
+		err string
+	}{
+		{"/abcd/ef", 4, ""},
+		{"/abcde/ef", 4, "invalid pointer: token exceeds maximum length of 4"},
+		{"/ef/abcde", 4, "invalid pointer: token exceeds maximum length of 4"},
+		{"/a~1b", 4, ""},
+		{"/a~1bc", 4, "invalid pointer: token exceeds maximum length of 4"},
+		{"/" + strings.Repeat("x", 1<<16), 0, ""},
+	}
+
+	for _, c := range cases {
+		d := Dialect{MaxTokenLength: c.max}
+		_, err := d.New(c.raw)
+		assertError(t, c.raw, err, c.err)
+	}
+}
+
+func TestDialectValidateUTF8(t *testing.T) {
+	cases := []struct {
+		raw      string
+		validate bool
+		expect   Pointer
+		err      string
+	}{
+		{"/foo/bär", true, Pointer{"foo", "bär"}, ""},
+		{"/foo/b\xffr", true, nil, "invalid pointer: token is not valid UTF-8"},
+		{"#/foo/b%FFr", true, nil, "invalid pointer: token is not valid UTF-8"},
+		{"/foo/b\xffr", false, Pointer{"foo", "b\xffr"}, ""},
+	}
+
+	for _, c := range cases {
+		d := Dialect{ValidateUTF8: c.validate}
+		got, err := d.New(c.raw)
+		if assertError(t, c.raw, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%q: value mismatch, expected: %#v, got: %#v", c.raw, c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package jsonpointer
+
+import (
+	"errors"
+	"reflect"
+)
+
+// DiffEntry describes a difference between two documents at a single location.
+type DiffEntry struct {
+	// Op is the kind of the difference: OpAdd if the value is only present in
+	// the second document, OpRemove if it is only present in the first one and
+	// OpReplace if it is present in both, but differs.
+	Op string
+
+	// Path is the location of the value.
+	Path Pointer
+
+	// Old is the value in the first document or nil, if it is absent.
+	Old interface{}
+
+	// New is the value in the second document or nil, if it is absent.
+	New interface{}
+}
+
+// DiffAt compares the two documents at the given pointers only and returns an
+// entry for each pointer at which they differ, in the order of the pointers.
+// Values are compared with reflect.DeepEqual. A pointer that resolves in
+// neither document is skipped. Errors other than failing to get a value are
+// returned.
+func DiffAt(a, b interface{}, ptrs []Pointer) ([]DiffEntry, error) {
+	var entries []DiffEntry
+	for _, p := range ptrs {
+		oldVal, oldOK, err := lookup(p, a)
+		if err != nil {
+			return nil, err
+		}
+		newVal, newOK, err := lookup(p, b)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case oldOK && newOK:
+			if !reflect.DeepEqual(oldVal, newVal) {
+				entries = append(entries, DiffEntry{Op: OpReplace, Path: p, Old: oldVal, New: newVal})
+			}
+		case oldOK:
+			entries = append(entries, DiffEntry{Op: OpRemove, Path: p, Old: oldVal})
+		case newOK:
+			entries = append(entries, DiffEntry{Op: OpAdd, Path: p, New: newVal})
+		}
+	}
+	return entries, nil
+}
+
+// lookup returns the value that the pointer points to and whether it is
+// present in the document.
+func lookup(p Pointer, doc interface{}) (interface{}, bool, error) {
+	value, err := p.Get(doc)
+	if err != nil {
+		if errors.Is(err, ErrGetFailed) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return value, true, nil
+}
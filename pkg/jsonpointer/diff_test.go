@@ -0,0 +1,56 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffAt(t *testing.T) {
+	a := map[string]interface{}{
+		"name":    "foo",
+		"version": 1,
+		"tags":    []interface{}{"a", "b"},
+		"owner":   "alice",
+		"meta":    map[string]interface{}{"created": "2020"},
+	}
+	b := map[string]interface{}{
+		"name":    "foo",
+		"version": 2,
+		"tags":    []interface{}{"a", "c"},
+		"license": "MIT",
+		"meta":    map[string]interface{}{"created": "2020"},
+	}
+
+	ptrs := []Pointer{
+		{"name"},
+		{"version"},
+		{"tags", "1"},
+		{"owner"},
+		{"license"},
+		{"meta"},
+		{"missing"},
+	}
+	expect := []DiffEntry{
+		{Op: OpReplace, Path: Pointer{"version"}, Old: 1, New: 2},
+		{Op: OpReplace, Path: Pointer{"tags", "1"}, Old: "b", New: "c"},
+		{Op: OpRemove, Path: Pointer{"owner"}, Old: "alice"},
+		{Op: OpAdd, Path: Pointer{"license"}, New: "MIT"},
+	}
+
+	got, err := DiffAt(a, b, ptrs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("value mismatch, expected: %#v, got: %#v", expect, got)
+	}
+
+	// identical documents have no differences
+	got, err = DiffAt(a, a, ptrs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("value mismatch, expected no entries, got: %#v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package jsonpointer
+
+import (
+	"strings"
+)
+
+// FromDotPath creates a new JSON pointer from a dot-separated path such as
+// "a.b[0].c". Dots are token separators, unless escaped as "\.", and bracketed
+// segments like "[0]" are array indices. A backslash escapes any character,
+// so a literal backslash is written as "\\". The empty path yields the empty
+// pointer.
+func FromDotPath(path string) (Pointer, error) {
+	newPtr := Pointer{}
+	if path == "" {
+		return newPtr, nil
+	}
+
+	var (
+		tok          strings.Builder
+		afterBracket bool
+	)
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if afterBracket && c != '.' && c != '[' {
+			return nil, newError(ErrInvalidJSONPointer, "unexpected character '%c' after index at offset %d", c, i)
+		}
+
+		switch c {
+		case '\\':
+			if i+1 >= len(path) {
+				return nil, newError(ErrInvalidJSONPointer, "incomplete escape sequence at offset %d", i)
+			}
+			i++
+			tok.WriteByte(path[i])
+
+		case '.':
+			if !afterBracket {
+				newPtr = append(newPtr, tok.String())
+				tok.Reset()
+			}
+			afterBracket = false
+
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, newError(ErrInvalidJSONPointer, "unterminated index at offset %d", i)
+			}
+			idx := path[i+1 : i+end]
+			if idx == "" || strings.Trim(idx, "0123456789") != "" {
+				return nil, newError(ErrInvalidJSONPointer, "invalid array index '%s' at offset %d", idx, i)
+			}
+			if tok.Len() > 0 || (i > 0 && !afterBracket && path[i-1] == '.') {
+				newPtr = append(newPtr, tok.String())
+				tok.Reset()
+			}
+			newPtr = append(newPtr, idx)
+			i += end
+			afterBracket = true
+
+		default:
+			tok.WriteByte(c)
+		}
+	}
+	if !afterBracket {
+		newPtr = append(newPtr, tok.String())
+	}
+	return newPtr, nil
+}
@@ -0,0 +1,42 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromDotPath(t *testing.T) {
+	cases := []struct {
+		path   string
+		expect Pointer
+		err    string
+	}{
+		{"", Pointer{}, ""},
+		{"a", Pointer{"a"}, ""},
+		{"a.b.c", Pointer{"a", "b", "c"}, ""},
+		{`a\.b.c`, Pointer{"a.b", "c"}, ""},
+		{`a\\.b`, Pointer{`a\`, "b"}, ""},
+		{"a[0].b", Pointer{"a", "0", "b"}, ""},
+		{"a[0][12]", Pointer{"a", "0", "12"}, ""},
+		{"[1].a", Pointer{"1", "a"}, ""},
+		{"a/b.c~d", Pointer{"a/b", "c~d"}, ""},
+		{"a..b", Pointer{"a", "", "b"}, ""},
+		{"a.", Pointer{"a", ""}, ""},
+		{"a.[0]", Pointer{"a", "", "0"}, ""},
+		{"a[0", nil, "invalid pointer: unterminated index at offset 1"},
+		{"a[x]", nil, "invalid pointer: invalid array index 'x' at offset 1"},
+		{"a[]", nil, "invalid pointer: invalid array index '' at offset 1"},
+		{"a[0]b", nil, "invalid pointer: unexpected character 'b' after index at offset 4"},
+		{`a\`, nil, "invalid pointer: incomplete escape sequence at offset 1"},
+	}
+
+	for _, c := range cases {
+		got, err := FromDotPath(c.path)
+		if assertError(t, c.path, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: expected: %#v, got: %#v", c.path, c.expect, got)
+		}
+	}
+}
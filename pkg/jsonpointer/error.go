@@ -1,6 +1,7 @@
 package jsonpointer
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -19,29 +20,67 @@ const (
 
 	// ErrSet indicates an error for setting a value.
 	ErrSet
+
+	// ErrIndexOutOfRange indicates an error for getting a value with an array
+	// index that exceeds the length of the array. It is a special case of
+	// ErrGet.
+	ErrIndexOutOfRange
+
+	// ErrTest indicates that a value does not match the expected value.
+	ErrTest
+
+	// ErrInvalidPatch indicates a malformed JSON Patch document or operation.
+	ErrInvalidPatch
+)
+
+// Sentinel errors for each ErrType. Errors returned by this package match the
+// sentinel of their type with errors.Is, e.g. errors.Is(err, ErrGetFailed).
+// Errors of type ErrIndexOutOfRange match both ErrOutOfRange and ErrGetFailed.
+var (
+	ErrUnknownFailed = errors.New("unknown")
+	ErrParseFailed   = errors.New("invalid pointer")
+	ErrGetFailed     = errors.New("get")
+	ErrSetFailed     = errors.New("set")
+	ErrOutOfRange    = errors.New("index out of range")
+	ErrTestFailed    = errors.New("test")
+	ErrPatchInvalid  = errors.New("invalid patch")
 )
 
 func (t ErrType) String() string {
 	switch t {
 	case ErrInvalidJSONPointer:
 		return "invalid pointer"
-	case ErrGet:
+	case ErrGet, ErrIndexOutOfRange:
 		return "get"
 	case ErrSet:
 		return "set"
+	case ErrTest:
+		return "test"
+	case ErrInvalidPatch:
+		return "invalid patch"
 	}
 	return "unknown"
 }
 
 // Error represents a JSON pointer error.
 type Error struct {
+	// Token is the token of the pointer at which getting or setting a value
+	// failed. It is only set if Index is not negative.
+	Token string
+
+	// Index is the index of Token in the pointer or -1, if the error is not
+	// related to a specific token.
+	Index int
+
 	msg     string
 	cause   error
 	errType ErrType
+	at      Pointer
 }
 
 func newError(errType ErrType, format string, args ...interface{}) *Error {
 	return &Error{
+		Index:   -1,
 		msg:     fmt.Sprintf(format, args...),
 		errType: errType,
 	}
@@ -49,18 +88,70 @@ func newError(errType ErrType, format string, args ...interface{}) *Error {
 
 func wrapError(err error, errType ErrType, format string, args ...interface{}) *Error {
 	return &Error{
+		Index:   -1,
 		msg:     fmt.Sprintf(format, args...),
 		cause:   err,
 		errType: errType,
 	}
 }
 
-// Error returns the formatted error message.
+// atToken returns a copy of the error that records the token of the pointer
+// at index i as the position of the failure. An index beyond the last token
+// refers to the last token. Errors that are not of type *Error or already
+// carry a position are returned unchanged.
+func atToken(err error, p Pointer, i int) error {
+	e, ok := err.(*Error)
+	if !ok || e.Index >= 0 || len(p) == 0 {
+		return err
+	}
+	if i >= len(p) {
+		i = len(p) - 1
+	}
+	cpy := *e
+	cpy.Token, cpy.Index, cpy.at = p[i], i, p[:i]
+	return &cpy
+}
+
+// Error returns the formatted error message. If the failure occurred below the
+// document root, the message includes the location of the value in which the
+// token could not be resolved, e.g. "get: at /foo/2: map has no key 'bar'".
 func (e *Error) Error() string {
+	if e.Index > 0 {
+		return fmt.Sprintf("%s: at %s: %s", e.errType, e.at, e.msg)
+	}
 	return fmt.Sprintf("%s: %s", e.errType, e.msg)
 }
 
+// Is reports whether the target is the sentinel error of the error's type, so
+// that e.g. errors.Is(err, ErrGetFailed) is true for errors of type ErrGet.
+func (e *Error) Is(target error) bool {
+	switch e.errType {
+	case ErrInvalidJSONPointer:
+		return target == ErrParseFailed
+	case ErrGet:
+		return target == ErrGetFailed
+	case ErrSet:
+		return target == ErrSetFailed
+	case ErrIndexOutOfRange:
+		return target == ErrOutOfRange || target == ErrGetFailed
+	case ErrTest:
+		return target == ErrTestFailed
+	case ErrInvalidPatch:
+		return target == ErrPatchInvalid
+	}
+	return target == ErrUnknownFailed
+}
+
 // Unwrap returns the underlying error.
 func (e *Error) Unwrap() error {
 	return e.cause
 }
+
+// errType returns the type of the error or ErrUnknown if it is not an *Error.
+func errType(err error) ErrType {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.errType
+	}
+	return ErrUnknown
+}
@@ -0,0 +1,78 @@
+package jsonpointer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	sentinels := []error{ErrUnknownFailed, ErrParseFailed, ErrGetFailed, ErrSetFailed, ErrPatchInvalid}
+
+	_, parseErr := NewStrict("/a~2")
+	_, getErr := Pointer{"missing"}.Get(map[string]interface{}{})
+	setErr := Pointer{"0"}.Set([1]int{}, 1)
+	_, patchErr := ApplyPatch(nil, []byte(`[{"op": "frob"}]`))
+
+	cases := []struct {
+		name   string
+		err    error
+		expect error
+	}{
+		{"unknown", newError(ErrUnknown, "failed"), ErrUnknownFailed},
+		{"parse", parseErr, ErrParseFailed},
+		{"get", getErr, ErrGetFailed},
+		{"set", setErr, ErrSetFailed},
+		{"patch", patchErr, ErrPatchInvalid},
+		{"wrapped", fmt.Errorf("loading config: %w", getErr), ErrGetFailed},
+	}
+
+	for _, c := range cases {
+		if c.err == nil {
+			t.Errorf("%s: expected an error", c.name)
+			continue
+		}
+		for _, sentinel := range sentinels {
+			if got := errors.Is(c.err, sentinel); got != (sentinel == c.expect) {
+				t.Errorf("%s: expected errors.Is(err, %q) to be %t", c.name, sentinel, !got)
+			}
+		}
+	}
+
+	// the cause is still matched
+	errBoom := errors.New("boom")
+	err := wrapError(errBoom, ErrGet, "failed: %s", errBoom)
+	if !errors.Is(err, errBoom) || !errors.Is(err, ErrGetFailed) {
+		t.Errorf("expected error to match its cause and its sentinel")
+	}
+}
+
+func TestErrorIndexOutOfRange(t *testing.T) {
+	doc := map[string]interface{}{"foo": []interface{}{"a", "b"}}
+
+	cases := []struct {
+		ptrstring  string
+		errType    ErrType
+		outOfRange bool
+		err        string
+	}{
+		{"/foo/3", ErrIndexOutOfRange, true, "get: at /foo: index 3 exceeds array length of 2"},
+		{"/foo/-1", ErrIndexOutOfRange, true, "get: at /foo: index -1 exceeds array length of 2"},
+		{"/foo/bar", ErrGet, false, "get: at /foo: invalid array index: bar"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		_, err := ptr.Get(doc)
+		if errType(err) != c.errType {
+			t.Errorf("%s: expected error of type %s, got: %s", c.ptrstring, c.errType, errType(err))
+		}
+		if got := errors.Is(err, ErrOutOfRange); got != c.outOfRange {
+			t.Errorf("%s: expected errors.Is(err, ErrOutOfRange) to be %t", c.ptrstring, c.outOfRange)
+		}
+		if !errors.Is(err, ErrGetFailed) {
+			t.Errorf("%s: expected error to match ErrGetFailed", c.ptrstring)
+		}
+		assertError(t, c.ptrstring, err, c.err)
+	}
+}
@@ -0,0 +1,45 @@
+package jsonpointer
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// GetFromFS resolves the pointer against a file system, using each token as a
+// file or directory name. It returns the contents of a file as []byte and the
+// sorted entry names of a directory as []string. The empty pointer addresses
+// the root directory.
+func GetFromFS(fsys fs.FS, p Pointer) (interface{}, error) {
+	for _, part := range p {
+		if part == "" || part == "." || part == ".." || strings.Contains(part, "/") {
+			return nil, newError(ErrGet, "invalid path segment '%s'", part)
+		}
+	}
+	name := "."
+	if len(p) > 0 {
+		name = strings.Join(p, "/")
+	}
+
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, wrapError(err, ErrGet, "failed to stat '%s': %s", name, err)
+	}
+
+	if info.IsDir() {
+		entries, err := fs.ReadDir(fsys, name)
+		if err != nil {
+			return nil, wrapError(err, ErrGet, "failed to read directory '%s': %s", name, err)
+		}
+		names := make([]string, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Name()
+		}
+		return names, nil
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, wrapError(err, ErrGet, "failed to read file '%s': %s", name, err)
+	}
+	return data, nil
+}
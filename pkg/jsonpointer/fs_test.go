@@ -0,0 +1,49 @@
+package jsonpointer
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGetFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/app.json":      {Data: []byte(`{"name": "app"}`)},
+		"config/db/main.yaml":  {Data: []byte("host: localhost")},
+		"config/db/extra.yaml": {Data: []byte("host: remote")},
+		"README":               {Data: []byte("hello")},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"", []string{"README", "config"}, ""},
+		{"/README", []byte("hello"), ""},
+		{"/config", []string{"app.json", "db"}, ""},
+		{"/config/db", []string{"extra.yaml", "main.yaml"}, ""},
+		{"/config/db/main.yaml", []byte("host: localhost"), ""},
+		{"/config/missing", nil, "get: failed to stat 'config/missing': open config/missing: file does not exist"},
+		{"/config/..", nil, "get: invalid path segment '..'"},
+		{"/config~1db", nil, "get: invalid path segment 'config/db'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := GetFromFS(fsys, ptr)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	ptr, _ := New("/missing")
+	if _, err := GetFromFS(fsys, ptr); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected error to wrap fs.ErrNotExist, got: %v", err)
+	}
+}
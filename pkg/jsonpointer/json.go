@@ -0,0 +1,333 @@
+package jsonpointer
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NonFiniteFloats controls how GetJSON encodes NaN and infinite floats, which
+// cannot be represented in JSON.
+type NonFiniteFloats int
+
+const (
+	// NonFiniteError makes GetJSON fail on NaN and infinite floats.
+	NonFiniteError NonFiniteFloats = iota
+
+	// NonFiniteNull encodes NaN and infinite floats as null.
+	NonFiniteNull
+
+	// NonFiniteString encodes NaN and infinite floats as string, see
+	// Resolver.NonFiniteString.
+	NonFiniteString
+)
+
+// GetJSON returns the JSON encoding of the value that the pointer points to.
+// See Resolver.GetJSON.
+func (p Pointer) GetJSON(doc interface{}) ([]byte, error) {
+	return defaultResolver.GetJSON(p, doc)
+}
+
+// GetJSON returns the JSON encoding of the value that the pointer points to.
+// NaN and infinite floats are handled as configured by
+// Resolver.NonFiniteFloats. To replace them, the value is converted into
+// generic maps and slices first, with structs being converted as visited by
+// Walk.
+func (r *Resolver) GetJSON(p Pointer, doc interface{}) ([]byte, error) {
+	value, err := r.Get(p, doc)
+	if err != nil {
+		return nil, err
+	}
+	if r.NonFiniteFloats != NonFiniteError && hasNonFinite(value) {
+		value = r.replaceNonFinite(reflect.ValueOf(value))
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, wrapError(err, ErrGet, "failed to encode value as JSON: %s", err)
+	}
+	return data, nil
+}
+
+// SetJSON decodes the JSON value and sets it at the pointer. See
+// Resolver.SetJSON.
+func (p Pointer) SetJSON(doc interface{}, raw json.RawMessage) error {
+	return defaultResolver.SetJSON(p, doc, raw)
+}
+
+// SetJSON decodes the JSON value and sets it at the pointer like Set. The JSON
+// is decoded into the type of the current value, e.g. the type of a struct
+// field, or into generic maps and slices if the value is held by an interface,
+// e.g. an element of a map[string]interface{}.
+func (r *Resolver) SetJSON(p Pointer, doc interface{}, raw json.RawMessage) error {
+	targetVal, err := r.Resolve(p, doc)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if targetVal.IsValid() && targetVal.Kind() != reflect.Interface {
+		newVal := reflect.New(targetVal.Type())
+		if err := json.Unmarshal(raw, newVal.Interface()); err != nil {
+			return wrapError(err, ErrSet, "failed to decode JSON: %s", err)
+		}
+		value = newVal.Elem().Interface()
+	} else if err := json.Unmarshal(raw, &value); err != nil {
+		return wrapError(err, ErrSet, "failed to decode JSON: %s", err)
+	}
+	return r.Set(p, doc, value)
+}
+
+var errNonFinite = errors.New("non-finite float")
+
+// hasNonFinite reports whether the value contains NaN or infinite floats.
+func hasNonFinite(value interface{}) bool {
+	err := Walk(value, func(p Pointer, value interface{}) error {
+		if val := reflect.ValueOf(value); val.Kind() == reflect.Float32 || val.Kind() == reflect.Float64 {
+			if f := val.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+				return errNonFinite
+			}
+		}
+		return nil
+	})
+	return err != nil
+}
+
+// replaceNonFinite converts the value into generic values for encoding,
+// replacing NaN and infinite floats as configured by Resolver.NonFiniteFloats.
+// Structs are converted into objects with the members that encoding/json
+// encodes, in the same order and with the same names and options. Values that
+// implement json.Marshaler or encoding.TextMarshaler are kept as is.
+func (r *Resolver) replaceNonFinite(val reflect.Value) interface{} {
+	if !val.IsValid() {
+		return nil
+	}
+	if val.CanInterface() {
+		switch v := val.Interface().(type) {
+		case json.Marshaler, encoding.TextMarshaler:
+			return v
+		}
+	}
+
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		return r.replaceNonFinite(val.Elem())
+
+	case reflect.Float32, reflect.Float64:
+		f := val.Float()
+		if !math.IsNaN(f) && !math.IsInf(f, 0) {
+			break
+		}
+		if r.NonFiniteFloats == NonFiniteNull {
+			return nil
+		}
+		if r.NonFiniteString != "" {
+			return r.NonFiniteString
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64)
+
+	case reflect.Map:
+		if val.IsNil() {
+			return nil
+		}
+		m := reflect.MakeMapWithSize(reflect.MapOf(val.Type().Key(), interfaceType), val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			elem := reflect.ValueOf(r.replaceNonFinite(iter.Value()))
+			if !elem.IsValid() {
+				elem = reflect.Zero(interfaceType)
+			}
+			m.SetMapIndex(iter.Key(), elem)
+		}
+		return m.Interface()
+
+	case reflect.Struct:
+		obj := jsonObject{}
+		for _, sf := range jsonFields(val.Type()) {
+			fieldVal, err := val.FieldByIndexErr(sf.Index)
+			if err != nil {
+				// embedded nil pointer
+				continue
+			}
+			opts := sf.Tag.Get(defaultTagName)
+			if hasTagOption(opts, "omitempty") && isEmptyValue(fieldVal) {
+				continue
+			}
+			name := tagName(sf, defaultTagName)
+			if name == "" {
+				name = sf.Name
+			}
+			value := r.replaceNonFinite(fieldVal)
+			if hasTagOption(opts, "string") && value != nil && isQuotable(fieldVal) {
+				data, _ := json.Marshal(value)
+				value = string(data)
+			}
+			obj = append(obj, jsonMember{name, value})
+		}
+		return obj
+
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.IsNil() {
+			return nil
+		}
+		s := make([]interface{}, val.Len())
+		for i := range s {
+			s[i] = r.replaceNonFinite(val.Index(i))
+		}
+		return s
+	}
+
+	if !val.CanInterface() {
+		return nil
+	}
+	return val.Interface()
+}
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// jsonMember is a member of a jsonObject.
+type jsonMember struct {
+	name  string
+	value interface{}
+}
+
+// jsonObject is a JSON object whose members are encoded in order.
+type jsonObject []jsonMember
+
+// MarshalJSON returns the JSON encoding of the object.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, m := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		name, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(append(buf, name...), ':'), value...)
+	}
+	return append(buf, '}'), nil
+}
+
+// jsonFields returns the fields of the struct type that encoding/json encodes,
+// including promoted fields of embedded structs, in encoding order.
+func jsonFields(st reflect.Type) []reflect.StructField {
+	var (
+		fields []reflect.StructField
+		names  = map[string]bool{}
+	)
+	current := []reflect.Type{st}
+	visited := map[reflect.Type]bool{}
+	for len(current) > 0 {
+		var next []reflect.Type
+		for _, t := range current {
+			if visited[t] {
+				continue
+			}
+			visited[t] = true
+			for i := 0; i < t.NumField(); i++ {
+				sf := t.Field(i)
+				if sf.Tag.Get(defaultTagName) == "-" {
+					continue
+				}
+				name := tagName(sf, defaultTagName)
+				if sf.Anonymous && name == "" {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, ft)
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = sf.Name
+				}
+				if names[name] {
+					continue
+				}
+				names[name] = true
+				if field, ok := findTaggedField(st, name, defaultTagName); ok {
+					fields = append(fields, field)
+				}
+			}
+		}
+		current = next
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].Index, fields[j].Index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+// hasTagOption reports whether the struct tag value has the option, e.g.
+// "omitempty" in "name,omitempty".
+func hasTagOption(tagVal, option string) bool {
+	opts := strings.Split(tagVal, ",")
+	for _, opt := range opts[1:] {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue reports whether the value is empty in the sense of the
+// "omitempty" option of encoding/json.
+func isEmptyValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return val.Len() == 0
+	case reflect.Bool:
+		return !val.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return val.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return val.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return val.IsNil()
+	}
+	return false
+}
+
+// isQuotable reports whether the "string" option of encoding/json applies to
+// the value, i.e. whether it is a finite number, a string or a bool, possibly
+// behind a pointer.
+func isQuotable(val reflect.Value) bool {
+	if val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	case reflect.Float32, reflect.Float64:
+		f := val.Float()
+		return !math.IsNaN(f) && !math.IsInf(f, 0)
+	}
+	return false
+}
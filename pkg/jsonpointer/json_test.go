@@ -0,0 +1,108 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetJSON(t *testing.T) {
+	type Reading struct {
+		Value float64   `json:"value"`
+		At    time.Time `json:"at"`
+	}
+	type Base struct {
+		ID int `json:"id"`
+	}
+	type Counter struct {
+		Base
+		Count int     `json:"count,string"`
+		Note  string  `json:"note,omitempty"`
+		V     float64 `json:"v"`
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	doc := map[string]interface{}{
+		"ok":       []float64{1, 2.5},
+		"readings": []interface{}{math.NaN(), math.Inf(1), math.Inf(-1), 1.5},
+		"struct":   Reading{Value: math.NaN(), At: at},
+		"finite":   Counter{Base: Base{ID: 1}, Count: 3, V: 1},
+		"counter":  Counter{Base: Base{ID: 1}, Count: 3, V: math.NaN()},
+	}
+
+	cases := []struct {
+		ptrstring string
+		policy    NonFiniteFloats
+		str       string
+		expect    string
+		err       string
+	}{
+		{"/ok", NonFiniteError, "", `[1,2.5]`, ""},
+		{"/readings", NonFiniteError, "", "", "get: failed to encode value as JSON: json: unsupported value: NaN"},
+		{"/readings", NonFiniteNull, "", `[null,null,null,1.5]`, ""},
+		{"/readings", NonFiniteString, "", `["NaN","+Inf","-Inf",1.5]`, ""},
+		{"/readings", NonFiniteString, "n/a", `["n/a","n/a","n/a",1.5]`, ""},
+		{"/struct", NonFiniteNull, "", `{"value":null,"at":"2024-01-02T03:04:05Z"}`, ""},
+		{"/finite", NonFiniteNull, "", `{"id":1,"count":"3","v":1}`, ""},
+		{"/counter", NonFiniteNull, "", `{"id":1,"count":"3","v":null}`, ""},
+		{"/counter", NonFiniteString, "", `{"id":1,"count":"3","v":"NaN"}`, ""},
+		{"/ok", NonFiniteNull, "", `[1,2.5]`, ""},
+		{"/missing", NonFiniteNull, "", "", "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		r := Resolver{NonFiniteFloats: c.policy, NonFiniteString: c.str}
+		ptr, _ := New(c.ptrstring)
+		got, err := r.GetJSON(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if string(got) != c.expect {
+			t.Errorf("%s: expected: %s, got: %s", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip"`
+	}
+	type Person struct {
+		Name    string                 `json:"name"`
+		Address Address                `json:"address"`
+		Tags    []string               `json:"tags"`
+		Home    *Address               `json:"home"`
+		Extra   map[string]interface{} `json:"extra"`
+	}
+
+	cases := []struct {
+		ptrstring string
+		raw       string
+		expect    interface{}
+		err       string
+	}{
+		{"/address", `{"city":"Berlin","zip":"10115"}`, Address{City: "Berlin", Zip: "10115"}, ""},
+		{"/tags", `["a","b"]`, []string{"a", "b"}, ""},
+		{"/home", `{"city":"Paris"}`, &Address{City: "Paris"}, ""},
+		{"/name", `"bob"`, "bob", ""},
+		{"/extra/obj", `{"a":[1,true]}`, map[string]interface{}{"a": []interface{}{1.0, true}}, ""},
+		{"/extra/list", `[{"b":null}]`, []interface{}{map[string]interface{}{"b": nil}}, ""},
+		{"/tags", `{"a":1}`, nil, "set: failed to decode JSON: json: cannot unmarshal object into Go value of type []string"},
+		{"/extra/obj", `{`, nil, "set: failed to decode JSON: unexpected end of JSON input"},
+		{"/missing", `1`, nil, "get: struct has no field 'missing'"},
+	}
+
+	for _, c := range cases {
+		doc := &Person{Extra: map[string]interface{}{"obj": nil, "list": nil}}
+		ptr, _ := New(c.ptrstring)
+		err := ptr.SetJSON(doc, json.RawMessage(c.raw))
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if got, _ := ptr.Get(doc); !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
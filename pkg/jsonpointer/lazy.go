@@ -0,0 +1,88 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// Loader is implemented by lazily loaded nodes of a document. If enabled with
+// Resolver.LoadLazy, Load is called when the resolution reaches the node and
+// the resolution continues with the returned value. This enables partial
+// loading of large documents from backing storage.
+type Loader interface {
+	// Load materializes the value of the node.
+	Load() (interface{}, error)
+}
+
+// LazyJSON is a Loader of a JSON value that is stored in a section of a
+// reader, e.g. a file or a memory-mapped document.
+type LazyJSON struct {
+	// R is the reader that holds the JSON value.
+	R io.ReaderAt
+	// Offset is the offset of the JSON value in R.
+	Offset int64
+	// Size is the size of the JSON value in bytes.
+	Size int64
+}
+
+// Load reads the JSON value from the reader and parses it into generic Go
+// values. A read that fills the section is accepted even if the reader
+// reports io.EOF, as allowed for sections at the end of the input.
+func (l LazyJSON) Load() (interface{}, error) {
+	data := make([]byte, l.Size)
+	if n, err := l.R.ReadAt(data, l.Offset); n < len(data) {
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	var val interface{}
+	if err := json.Unmarshal(data, &val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// asLoader returns the value as Loader, if it implements the interface.
+func asLoader(val reflect.Value) (Loader, bool) {
+	if !val.IsValid() || !val.CanInterface() || isNil(val) {
+		return nil, false
+	}
+	if l, ok := val.Interface().(Loader); ok {
+		return l, true
+	}
+	if val.CanAddr() {
+		if l, ok := val.Addr().Interface().(Loader); ok {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// load loads the node. If the loaded value is a Loader itself, it is loaded as
+// well, until a regular value is reached. Loading a node again that was
+// already loaded in the process fails.
+func load(l Loader) (reflect.Value, error) {
+	var loaded []Loader
+	for {
+		if reflect.TypeOf(l).Comparable() {
+			for _, prev := range loaded {
+				if prev == l {
+					return reflect.Value{}, newError(ErrGet, "cyclic lazy node of type %T", l)
+				}
+			}
+			loaded = append(loaded, l)
+		}
+		val, err := l.Load()
+		if err != nil {
+			return reflect.Value{}, wrapError(err, ErrGet, "failed to load document value: %s", err)
+		}
+		valVal := reflect.ValueOf(val)
+		next, ok := asLoader(valVal)
+		if !ok {
+			return valVal, nil
+		}
+		l = next
+	}
+}
@@ -0,0 +1,95 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// cyclicNode is a lazy node that loads itself.
+type cyclicNode struct{}
+
+func (n *cyclicNode) Load() (interface{}, error) { return n, nil }
+
+// failingNode is a lazy node that fails to load.
+type failingNode struct{}
+
+func (failingNode) Load() (interface{}, error) { return nil, errors.New("disk on fire") }
+
+// eofReader is a reader that returns io.EOF along with reads that reach the
+// end of the input, as permitted by io.ReaderAt.
+type eofReader struct {
+	data []byte
+}
+
+func (r eofReader) ReadAt(p []byte, off int64) (int, error) {
+	n := copy(p, r.data[off:])
+	if off+int64(n) == int64(len(r.data)) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func TestResolverLoadLazy(t *testing.T) {
+	storage := []byte(`{"host":"localhost","ports":[80,443]}["a","b"]{"x":1`)
+	r := bytes.NewReader(storage)
+	doc := map[string]interface{}{
+		"server": LazyJSON{R: r, Offset: 0, Size: 37},
+		"tags":   &LazyJSON{R: r, Offset: 37, Size: 9},
+		"nested": LazyJSON{R: bytes.NewReader([]byte(`{"inner":"x"}`)), Size: 13},
+		"broken": LazyJSON{R: r, Offset: 46, Size: 6},
+		"short":  LazyJSON{R: r, Offset: 46, Size: 100},
+		"end":    LazyJSON{R: eofReader{storage[:46]}, Offset: 37, Size: 9},
+		"cyclic": &cyclicNode{},
+		"fail":   failingNode{},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/server/host", "localhost", ""},
+		{"/server/ports/1", 443.0, ""},
+		{"/server", map[string]interface{}{"host": "localhost", "ports": []interface{}{80.0, 443.0}}, ""},
+		{"/tags/1", "b", ""},
+		{"/nested/inner", "x", ""},
+		{"/server/missing", nil, "get: at /server: map has no key 'missing'"},
+		{"/broken/x", nil, "get: at /broken: failed to load document value: unexpected end of JSON input"},
+		{"/short", nil, "get: failed to load document value: EOF"},
+		{"/end/0", "a", ""},
+		{"/cyclic/x", nil, "get: at /cyclic: cyclic lazy node of type *jsonpointer.cyclicNode"},
+		{"/fail", nil, "get: failed to load document value: disk on fire"},
+	}
+
+	for _, c := range cases {
+		r := Resolver{LoadLazy: true}
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// lazy nodes are not loaded by default
+	ptr, _ := New("/server/host")
+	_, err := ptr.Get(doc)
+	assertError(t, ptr.String(), err, "get: at /server: struct has no field 'host'")
+
+	// loaded values cannot be set, as they are not part of the document
+	lazyResolver := Resolver{LoadLazy: true}
+	err = lazyResolver.Set(ptr, doc, "example.com")
+	assertError(t, ptr.String(), err, "set: at /server: cannot set value on unaddressable document or unexported field")
+	var perr *Error
+	if !errors.Is(err, ErrSetFailed) || !errors.As(err, &perr) {
+		t.Fatalf("%s: expected error to match %q, got: %v", ptr, ErrSetFailed, err)
+	}
+	if perr.Token != "host" || perr.Index != 1 {
+		t.Errorf("%s: expected token 'host' at index 1, got: '%s' at %d", ptr, perr.Token, perr.Index)
+	}
+}
@@ -0,0 +1,45 @@
+package jsonpointer
+
+import (
+	"container/list"
+	"reflect"
+	"strconv"
+)
+
+// GetFromList returns the value from the given list that the pointer points
+// to. Lists are treated as arrays, indexed by the position of their elements.
+// Element values are resolved recursively, so they may be maps, slices,
+// structs or lists themselves.
+func GetFromList(l *list.List, p Pointer) (interface{}, error) {
+	var cur interface{} = l
+	for _, part := range p {
+		if l, ok := cur.(*list.List); ok {
+			if l == nil {
+				return nil, newError(ErrGet, "document value is nil")
+			}
+			i, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, newError(ErrGet, "invalid array index: %s", part)
+			}
+			if i < 0 || i >= l.Len() {
+				return nil, newError(ErrIndexOutOfRange, "index %d exceeds array length of %d", i, l.Len())
+			}
+			e := l.Front()
+			for ; i > 0; i-- {
+				e = e.Next()
+			}
+			cur = e.Value
+			continue
+		}
+
+		childVal, err := defaultResolver.getValue(reflect.ValueOf(cur), part)
+		if err != nil {
+			return nil, err
+		}
+		if !childVal.CanInterface() {
+			return nil, newError(ErrGet, "cannot get document value")
+		}
+		cur = childVal.Interface()
+	}
+	return cur, nil
+}
@@ -0,0 +1,44 @@
+package jsonpointer
+
+import (
+	"container/list"
+	"reflect"
+	"testing"
+)
+
+func TestGetFromList(t *testing.T) {
+	tags := list.New()
+	tags.PushBack("a")
+	tags.PushBack("b")
+
+	l := list.New()
+	l.PushBack(map[string]interface{}{"name": "foo"})
+	l.PushBack(map[string]interface{}{"name": "bar", "tags": tags})
+	l.PushBack([]interface{}{1, 2})
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"", l, ""},
+		{"/0/name", "foo", ""},
+		{"/1/tags/1", "b", ""},
+		{"/2/0", 1, ""},
+		{"/3", nil, "get: index 3 exceeds array length of 3"},
+		{"/1/tags/2", nil, "get: index 2 exceeds array length of 2"},
+		{"/foo", nil, "get: invalid array index: foo"},
+		{"/0/missing", nil, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := GetFromList(l, ptr)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
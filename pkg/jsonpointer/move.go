@@ -0,0 +1,63 @@
+package jsonpointer
+
+import "reflect"
+
+// Move moves the value at the from pointer to the location that the pointer
+// points to. See Resolver.Move.
+func (p Pointer) Move(doc interface{}, from Pointer) error {
+	return defaultResolver.Move(p, doc, from)
+}
+
+// Move moves the value at the from pointer to the location that the pointer
+// points to, following the semantics of the JSON Patch "move" operation
+// (RFC 6902): the value is removed at the source and then added at the
+// destination. The destination is resolved after the removal, so that moving
+// an element within the same array addresses its final position. If the value
+// cannot be added at the destination, it is restored at the source, leaving
+// the document unchanged. A value cannot be moved into one of its own
+// children.
+func (r *Resolver) Move(p Pointer, doc interface{}, from Pointer) error {
+	if p.HasPrefix(from) {
+		if len(p) == len(from) {
+			_, err := r.Get(from, doc)
+			return err
+		}
+		return newError(ErrSet, "cannot move value at %s into its child %s", from, p)
+	}
+	value, err := r.Get(from, doc)
+	if err != nil {
+		return err
+	}
+	if err := r.Delete(from, doc); err != nil {
+		return err
+	}
+	if err := r.Add(p, doc, value); err != nil {
+		// put the value back, so that the document is left unchanged
+		if restoreErr := r.Add(from, doc, value); restoreErr != nil {
+			return restoreErr
+		}
+		return err
+	}
+	return nil
+}
+
+// Copy copies the value at the from pointer to the location that the pointer
+// points to. See Resolver.Copy.
+func (p Pointer) Copy(doc interface{}, from Pointer) error {
+	return defaultResolver.Copy(p, doc, from)
+}
+
+// Copy copies the value at the from pointer to the location that the pointer
+// points to, following the semantics of the JSON Patch "copy" operation
+// (RFC 6902). The value is copied deeply, so that the copy does not share maps,
+// slices or pointers with the original.
+func (r *Resolver) Copy(p Pointer, doc interface{}, from Pointer) error {
+	value, err := r.Get(from, doc)
+	if err != nil {
+		return err
+	}
+	if value != nil {
+		value = deepCopy(reflect.ValueOf(value)).Interface()
+	}
+	return r.Add(p, doc, value)
+}
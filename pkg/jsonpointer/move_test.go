@@ -0,0 +1,77 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMove(t *testing.T) {
+	cases := []struct {
+		name   string
+		doc    string
+		from   string
+		path   string
+		expect string
+		err    string
+	}{
+		// examples from RFC 6902, appendix A
+		{"move value", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, "/foo/waldo", "/qux/thud", `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`, ""},
+		{"move array element", `{"foo":["all","grass","cows","eat"]}`, "/foo/1", "/foo/3", `{"foo":["all","cows","eat","grass"]}`, ""},
+
+		{"move array element down", `{"foo":["a","b","c","d"]}`, "/foo/3", "/foo/0", `{"foo":["d","a","b","c"]}`, ""},
+		{"move array element to end", `{"foo":["a","b","c"]}`, "/foo/0", "/foo/-", `{"foo":["b","c","a"]}`, ""},
+		{"move between arrays", `{"a":[1,2],"b":[3]}`, "/a/0", "/b/0", `{"a":[2],"b":[1,3]}`, ""},
+		{"move to itself", `{"foo":["a","b"]}`, "/foo/1", "/foo/1", `{"foo":["a","b"]}`, ""},
+		{"move into child", `{"foo":{"bar":1}}`, "/foo", "/foo/bar/baz", "", "set: cannot move value at /foo into its child /foo/bar/baz"},
+		{"missing source", `{"foo":["a"]}`, "/foo/1", "/foo/0", "", "get: at /foo: index 1 exceeds array length of 1"},
+		{"destination out of range", `{"foo":["a","b"]}`, "/foo/0", "/foo/2", "", "get: index 2 exceeds array length of 1"},
+		{"destination parent missing", `{"foo":["a","b"]}`, "/foo/1", "/n/x", "", "get: map has no key 'n'"},
+		{"destination in scalar", `{"foo":{"bar":1,"baz":"x"}}`, "/foo/bar", "/foo/baz/qux", "", "set: cannot add token 'qux' to value of type string"},
+	}
+
+	for _, c := range cases {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(c.doc), &doc); err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		from, _ := New(c.from)
+		ptr, _ := New(c.path)
+		err := ptr.Move(doc, from)
+		if assertError(t, c.name, err, c.err) {
+			// a failed move leaves the document unchanged
+			if got, _ := json.Marshal(doc); err != nil && string(got) != c.doc {
+				t.Errorf("%s: document changed, expected: %s, got: %s", c.name, c.doc, got)
+			}
+			continue
+		}
+		got, _ := json.Marshal(doc)
+		if string(got) != c.expect {
+			t.Errorf("%s: value mismatch, expected: %s, got: %s", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{"foo":[{"bar":[1]}],"baz":{}}`), &doc)
+
+	if err := (Pointer{"foo", "-"}).Copy(doc, Pointer{"foo", "0"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := (Pointer{"baz", "qux"}).Copy(doc, Pointer{"foo", "0"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// mutating the original must not affect the copies
+	if err := (Pointer{"foo", "0", "bar", "-"}).Add(doc, 2); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expect := `{"baz":{"qux":{"bar":[1]}},"foo":[{"bar":[1,2]},{"bar":[1]}]}`
+	if got, _ := json.Marshal(doc); string(got) != expect {
+		t.Errorf("value mismatch, expected: %s, got: %s", expect, got)
+	}
+
+	err := Pointer{"baz", "x"}.Copy(doc, Pointer{"missing"})
+	assertError(t, "/missing", err, "get: map has no key 'missing'")
+}
@@ -0,0 +1,92 @@
+package jsonpointer
+
+import "reflect"
+
+// DescentEvent describes the resolution of a single token of a pointer.
+type DescentEvent struct {
+	// Index is the index of the token in the pointer.
+	Index int
+
+	// Token is the resolved token.
+	Token string
+
+	// ContainerKind is the kind of the value that the token was resolved in.
+	ContainerKind reflect.Kind
+
+	// Kind is the kind of the value that the token resolved to. Pointers and
+	// interfaces are dereferenced for both kinds.
+	Kind reflect.Kind
+}
+
+// Observe resolves the pointer in the background and emits an event for each
+// resolved token. See Resolver.Observe.
+func (p Pointer) Observe(doc interface{}, done <-chan struct{}) (<-chan DescentEvent, <-chan error) {
+	return defaultResolver.Observe(p, doc, done)
+}
+
+// Observe resolves the pointer in a separate goroutine and emits a
+// DescentEvent for each resolved token on the returned event channel, e.g. for
+// visualizing the resolution. The pointer is resolved like GetTraced does,
+// including links and lazy nodes, if enabled. The event channel is closed when
+// the resolution is done. If a token cannot be resolved, the error is sent on
+// the error channel, which is closed after the event channel. Closing done
+// stops the emission of events early, so that the goroutine exits even if the
+// consumer stops receiving events.
+func (r *Resolver) Observe(p Pointer, doc interface{}, done <-chan struct{}) (<-chan DescentEvent, <-chan error) {
+	events := make(chan DescentEvent)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(events)
+
+		// the event of a token is emitted when the next token is traced, which
+		// receives the kind of the value the token resolved to
+		var (
+			containerKind reflect.Kind
+			stopped       bool
+		)
+		emit := func(i int, kind reflect.Kind) {
+			if stopped {
+				return
+			}
+			event := DescentEvent{
+				Index:         i,
+				Token:         p[i],
+				ContainerKind: containerKind,
+				Kind:          kind,
+			}
+			select {
+			case events <- event:
+			case <-done:
+				stopped = true
+			}
+		}
+		trace := func(i int, _ string, kind reflect.Kind) {
+			if i > 0 {
+				emit(i-1, kind)
+			}
+			containerKind = kind
+		}
+
+		resultVal, i, err := r.resolve(p, doc, trace)
+		if err == nil && r.LoadLazy {
+			if l, ok := asLoader(resultVal); ok {
+				i = len(p)
+				resultVal, err = load(l)
+			}
+		}
+		if stopped {
+			return
+		}
+		if err != nil {
+			err = atToken(err, p, i)
+			r.reportError(p, i, err)
+			errc <- err
+			return
+		}
+		if len(p) > 0 {
+			emit(len(p)-1, indirect(resultVal).Kind())
+		}
+	}()
+	return events, errc
+}
@@ -0,0 +1,106 @@
+package jsonpointer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestObserve(t *testing.T) {
+	type Item struct {
+		Tags []string `json:"tags"`
+	}
+	doc := map[string]interface{}{
+		"items": []interface{}{&Item{Tags: []string{"a", "b"}}},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    []DescentEvent
+		err       string
+	}{
+		{
+			"/items/0/tags/1",
+			[]DescentEvent{
+				{0, "items", reflect.Map, reflect.Slice},
+				{1, "0", reflect.Slice, reflect.Struct},
+				{2, "tags", reflect.Struct, reflect.Slice},
+				{3, "1", reflect.Slice, reflect.String},
+			},
+			"",
+		},
+		{"", nil, ""},
+		{
+			"/items/0/missing",
+			[]DescentEvent{
+				{0, "items", reflect.Map, reflect.Slice},
+				{1, "0", reflect.Slice, reflect.Struct},
+			},
+			"get: at /items/0: struct has no field 'missing'",
+		},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		events, errc := ptr.Observe(doc, nil)
+		var got []DescentEvent
+		for event := range events {
+			got = append(got, event)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+		assertError(t, c.ptrstring, <-errc, c.err)
+	}
+}
+
+func TestObserveLoadLazy(t *testing.T) {
+	doc := map[string]interface{}{
+		"lazy":   LazyJSON{R: bytes.NewReader([]byte(`{"x":1}`)), Size: 7},
+		"broken": LazyJSON{R: bytes.NewReader([]byte(`{"x":`)), Size: 5},
+	}
+	r := Resolver{LoadLazy: true}
+
+	// the value at the end of the pointer is loaded like GetTraced does
+	ptr, _ := New("/lazy")
+	events, errc := r.Observe(ptr, doc, nil)
+	var got []DescentEvent
+	for event := range events {
+		got = append(got, event)
+	}
+	expect := []DescentEvent{{0, "lazy", reflect.Map, reflect.Map}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, expect, got)
+	}
+	assertError(t, ptr.String(), <-errc, "")
+
+	ptr, _ = New("/broken")
+	events, errc = r.Observe(ptr, doc, nil)
+	for range events {
+		t.Errorf("%s: expected no events", ptr)
+	}
+	assertError(t, ptr.String(), <-errc, "get: failed to load document value: unexpected end of JSON input")
+}
+
+func TestObserveDone(t *testing.T) {
+	doc := map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": 1}}}
+	ptr, _ := New("/a/b/c")
+
+	done := make(chan struct{})
+	events, errc := ptr.Observe(doc, done)
+	if event := <-events; event.Token != "a" {
+		t.Errorf("value mismatch, expected: %#v, got: %#v", "a", event.Token)
+	}
+
+	// the goroutine exits without further events being received
+	close(done)
+	select {
+	case err, ok := <-errc:
+		if ok {
+			t.Errorf("expected no error, got: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the error channel to be closed")
+	}
+}
@@ -0,0 +1,349 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// JSON Patch operation types as defined in RFC 6902.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Operation represents a single JSON Patch operation (RFC 6902).
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Patch represents a JSON Patch document (RFC 6902).
+type Patch []Operation
+
+// MarshalJSON returns the JSON encoding of the operation. The value is only
+// included for operations that take one, where it is always included, even if
+// it is null.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	switch o.Op {
+	case OpAdd, OpReplace, OpTest:
+		return json.Marshal(struct {
+			Op    string      `json:"op"`
+			Path  string      `json:"path"`
+			Value interface{} `json:"value"`
+		}{o.Op, o.Path, o.Value})
+
+	case OpMove, OpCopy:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			From string `json:"from"`
+			Path string `json:"path"`
+		}{o.Op, o.From, o.Path})
+	}
+	type operation Operation
+	return json.Marshal(operation(o))
+}
+
+// AddOp returns an operation that adds the value at the pointer.
+func (p Pointer) AddOp(value interface{}) Operation {
+	return Operation{Op: OpAdd, Path: p.String(), Value: value}
+}
+
+// RemoveOp returns an operation that removes the value at the pointer.
+func (p Pointer) RemoveOp() Operation {
+	return Operation{Op: OpRemove, Path: p.String()}
+}
+
+// ReplaceOp returns an operation that replaces the value at the pointer.
+func (p Pointer) ReplaceOp(value interface{}) Operation {
+	return Operation{Op: OpReplace, Path: p.String(), Value: value}
+}
+
+// ValidatePaths checks that the "path" and, for move and copy operations, the
+// "from" of every operation is a well-formed JSON pointer with strict
+// validation. It returns an error that aggregates all failures by operation
+// index, or nil if all pointers are valid.
+func (pt Patch) ValidatePaths() error {
+	var errs []error
+	for i, op := range pt {
+		if _, err := strictDialect.parse(op.Path); err != nil {
+			errs = append(errs, fmt.Errorf("operation %d: path '%s': %w", i, op.Path, err))
+		}
+		if op.Op == OpMove || op.Op == OpCopy {
+			if _, err := strictDialect.parse(op.From); err != nil {
+				errs = append(errs, fmt.Errorf("operation %d: from '%s': %w", i, op.From, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Optimize returns a copy of the patch with redundant operations removed. A
+// replace or remove supersedes a preceding replace of the same path and a
+// replace is folded into a preceding add of the same path. A remove cancels a
+// preceding add of the same path only if the add itself follows a remove of
+// that path, as the add could otherwise overwrite an existing object member or
+// fail. Operations are only collapsed if no operation in between touches an
+// overlapping location, so that the optimized patch has the same effect.
+func (pt Patch) Optimize() Patch {
+	out := make(Patch, 0, len(pt))
+	for _, op := range pt {
+		if op.Op == OpReplace || op.Op == OpRemove {
+			if i := lastOverlapping(out, op); i >= 0 && out[i].Path == op.Path {
+				switch prev := out[i].Op; {
+				case prev == OpReplace:
+					out = append(out[:i], out[i+1:]...)
+				case prev == OpAdd && op.Op == OpReplace:
+					out[i].Value = op.Value
+					continue
+				case prev == OpAdd && op.Op == OpRemove && followsRemove(out[:i], out[i]):
+					out = append(out[:i], out[i+1:]...)
+					continue
+				}
+			}
+		}
+		out = append(out, op)
+	}
+	return out
+}
+
+// followsRemove reports whether the last operation in ops that overlaps with op
+// is a remove of the same path.
+func followsRemove(ops Patch, op Operation) bool {
+	i := lastOverlapping(ops, op)
+	return i >= 0 && ops[i].Op == OpRemove && ops[i].Path == op.Path
+}
+
+// lastOverlapping returns the index of the last operation in ops that touches a
+// location overlapping with one touched by op, or -1 if there is none.
+func lastOverlapping(ops Patch, op Operation) int {
+	for i := len(ops) - 1; i >= 0; i-- {
+		for _, a := range ops[i].paths() {
+			for _, b := range op.paths() {
+				if pathsOverlap(a, b) {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// paths returns the paths that the operation reads or writes.
+func (o Operation) paths() []string {
+	if o.Op == OpMove || o.Op == OpCopy {
+		return []string{o.Path, o.From}
+	}
+	return []string{o.Path}
+}
+
+// pathsOverlap reports whether an operation on one path may affect the other,
+// i.e. one is a prefix of the other or they diverge at array indices, which
+// may shift due to insertions and removals. Invalid paths overlap with all
+// others.
+func pathsOverlap(a, b string) bool {
+	pa, errA := New(a)
+	pb, errB := New(b)
+	if errA != nil || errB != nil {
+		return true
+	}
+	if k := pa.DivergeAt(pb); k < len(pa) && k < len(pb) {
+		return isArrayIndex(pa[k]) && isArrayIndex(pb[k])
+	}
+	return true
+}
+
+// isArrayIndex reports whether the token may refer to an array element.
+func isArrayIndex(tok string) bool {
+	if tok == "-" {
+		return true
+	}
+	_, err := strconv.ParseUint(tok, 10, 64)
+	return err == nil
+}
+
+// rawOperation is a JSON Patch operation as it is decoded by ApplyPatch. The
+// members are kept raw, so that absent members can be told apart from null.
+type rawOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyPatch applies the JSON Patch document (RFC 6902) to a deep copy of the
+// document and returns the patched copy. The operations are applied in order.
+// If an operation is malformed or fails, an error identifying the index of the
+// operation is returned and the document is left unchanged. The root of generic
+// documents, as decoded by encoding/json into an interface{}, can be replaced
+// by a value of another JSON type.
+func ApplyPatch(doc interface{}, patch []byte) (interface{}, error) {
+	var ops []rawOperation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, wrapError(err, ErrInvalidPatch, "%s", err)
+	}
+
+	// operate on a pointer to the copy, so that the root is addressable.
+	// Generic documents are held in an interface, so that the root can be
+	// replaced by a value of another JSON type.
+	var rootVal reflect.Value
+	if isGenericJSON(doc) {
+		rootVal = reflect.New(interfaceType)
+	} else {
+		rootVal = reflect.New(reflect.TypeOf(doc))
+	}
+	if doc != nil {
+		rootVal.Elem().Set(deepCopy(reflect.ValueOf(doc)))
+	}
+	for i, op := range ops {
+		if err := applyOperation(rootVal, op); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return rootVal.Elem().Interface(), nil
+}
+
+// isGenericJSON reports whether the document consists of generic values as
+// decoded by encoding/json into an interface{}, i.e. it is nil or a
+// map[string]interface{}, []interface{}, string, float64 or bool.
+func isGenericJSON(doc interface{}) bool {
+	switch doc.(type) {
+	case nil, map[string]interface{}, []interface{}, string, float64, bool:
+		return true
+	}
+	return false
+}
+
+// applyOperation validates and applies a single operation to the document that
+// rootVal points to. Malformed operations yield errors of type ErrInvalidPatch
+// and invalid paths errors of type ErrInvalidJSONPointer.
+func applyOperation(rootVal reflect.Value, op rawOperation) error {
+	switch op.Op {
+	case OpAdd, OpRemove, OpReplace, OpMove, OpCopy, OpTest:
+	case "":
+		return newError(ErrInvalidPatch, "missing 'op'")
+	default:
+		return newError(ErrInvalidPatch, "unknown operation '%s'", op.Op)
+	}
+
+	if op.Path == nil {
+		return newError(ErrInvalidPatch, "missing 'path'")
+	}
+	path, err := strictDialect.parse(*op.Path)
+	if err != nil {
+		return fmt.Errorf("path '%s': %w", *op.Path, err)
+	}
+	var from Pointer
+	if op.Op == OpMove || op.Op == OpCopy {
+		if op.From == nil {
+			return newError(ErrInvalidPatch, "missing 'from'")
+		}
+		if from, err = strictDialect.parse(*op.From); err != nil {
+			return fmt.Errorf("from '%s': %w", *op.From, err)
+		}
+	}
+	var value interface{}
+	if op.Op == OpAdd || op.Op == OpReplace || op.Op == OpTest {
+		if op.Value == nil {
+			return newError(ErrInvalidPatch, "missing 'value'")
+		}
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return wrapError(err, ErrInvalidPatch, "invalid 'value': %s", err)
+		}
+	}
+
+	// apply operations below the root of a generic document to an addressable
+	// copy of its value and store it back afterwards
+	if len(path) > 0 && rootVal.Elem().Kind() == reflect.Interface && !rootVal.Elem().IsNil() {
+		ifaceVal := rootVal.Elem()
+		rootVal = reflect.New(ifaceVal.Elem().Type())
+		rootVal.Elem().Set(ifaceVal.Elem())
+		defer func() { ifaceVal.Set(rootVal.Elem()) }()
+	}
+
+	// remove and replace require the target to exist
+	doc := rootVal.Interface()
+	if op.Op == OpRemove || op.Op == OpReplace {
+		if _, err := path.Get(doc); err != nil {
+			return err
+		}
+	}
+
+	switch op.Op {
+	case OpAdd:
+		if len(path) == 0 {
+			return defaultResolver.setValue(rootVal.Elem(), value)
+		}
+		return path.Add(doc, value)
+
+	case OpRemove:
+		return path.Delete(doc)
+
+	case OpReplace:
+		if len(path) == 0 {
+			return defaultResolver.setValue(rootVal.Elem(), value)
+		}
+		if err := path.Delete(doc); err != nil {
+			return err
+		}
+		return path.Add(doc, value)
+
+	case OpMove:
+		return path.Move(doc, from)
+
+	case OpCopy:
+		return path.Copy(doc, from)
+	}
+
+	// OpTest
+	return path.Test(doc, value)
+}
+
+// Test checks that the value that the pointer points to equals the expected
+// value, following the semantics of the JSON Patch "test" operation
+// (RFC 6902). Values are compared by their JSON structure, so that numbers of
+// different types are equal if they have the same value and structs equal
+// maps with the same members. An error of type ErrTest is returned if the
+// values differ.
+func (p Pointer) Test(doc, expected interface{}) error {
+	value, err := p.Get(doc)
+	if err != nil {
+		return err
+	}
+	if !jsonEqual(value, expected) {
+		return newError(ErrTest, "value at '%s' does not match", p)
+	}
+	return nil
+}
+
+// jsonEqual reports whether the values are deeply equal or have the same JSON
+// structure, e.g. an int and a float64 of the same number.
+func jsonEqual(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	na, errA := jsonNormalize(a)
+	nb, errB := jsonNormalize(b)
+	return errA == nil && errB == nil && reflect.DeepEqual(na, nb)
+}
+
+// jsonNormalize returns the value as it is decoded from its JSON encoding into
+// an interface{}, i.e. as a tree of maps, slices, strings, float64s, bools and
+// nils.
+func jsonNormalize(value interface{}) (interface{}, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(b, &normalized)
+	return normalized, err
+}
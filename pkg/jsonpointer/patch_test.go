@@ -0,0 +1,427 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPatchOperations(t *testing.T) {
+	ptr, _ := New("/foo/a~1b/0")
+	patch := Patch{
+		ptr.AddOp(map[string]interface{}{"bar": 1}),
+		ptr.AddOp(nil),
+		ptr.ReplaceOp(false),
+		ptr.RemoveOp(),
+		Pointer{}.ReplaceOp([]int{1, 2}),
+	}
+
+	got, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	expect := `[` +
+		`{"op":"add","path":"/foo/a~1b/0","value":{"bar":1}},` +
+		`{"op":"add","path":"/foo/a~1b/0","value":null},` +
+		`{"op":"replace","path":"/foo/a~1b/0","value":false},` +
+		`{"op":"remove","path":"/foo/a~1b/0"},` +
+		`{"op":"replace","path":"","value":[1,2]}` +
+		`]`
+	if string(got) != expect {
+		t.Errorf("expected: %s, got: %s", expect, got)
+	}
+}
+
+func TestPatchValidatePaths(t *testing.T) {
+	valid := Patch{
+		{Op: OpAdd, Path: "/foo/0", Value: 1},
+		{Op: OpRemove, Path: "/a~1b"},
+		{Op: OpReplace, Path: "", Value: 1},
+		{Op: OpMove, From: "/foo", Path: "/bar"},
+		{Op: OpCopy, From: "/foo/~0", Path: "/bar"},
+	}
+	if err := valid.ValidatePaths(); err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+
+	invalid := Patch{
+		{Op: OpAdd, Path: "/foo/0", Value: 1},
+		{Op: OpReplace, Path: "foo", Value: 1},
+		{Op: OpMove, From: "/foo~2", Path: "/bar"},
+		{Op: OpCopy, From: "/foo", Path: "/bar~"},
+	}
+	err := invalid.ValidatePaths()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	expect := "operation 1: path 'foo': invalid pointer: non-empty references must begin with a '/' character\n" +
+		"operation 2: from '/foo~2': invalid pointer: invalid escape sequence at offset 3 in token 'foo~2'\n" +
+		"operation 3: path '/bar~': invalid pointer: invalid escape sequence at offset 3 in token 'bar~'"
+	if err.Error() != expect {
+		t.Errorf("expected error:\n%s\ngot:\n%s", expect, err)
+	}
+}
+
+func TestPatchOptimize(t *testing.T) {
+	cases := []struct {
+		patch  Patch
+		expect Patch
+	}{
+		// redundant replaces
+		{
+			Patch{
+				{Op: OpReplace, Path: "/foo", Value: 1},
+				{Op: OpReplace, Path: "/bar", Value: 1},
+				{Op: OpReplace, Path: "/foo", Value: 2},
+			},
+			Patch{
+				{Op: OpReplace, Path: "/bar", Value: 1},
+				{Op: OpReplace, Path: "/foo", Value: 2},
+			},
+		},
+		{
+			Patch{
+				{Op: OpReplace, Path: "/foo", Value: 1},
+				{Op: OpRemove, Path: "/foo"},
+			},
+			Patch{
+				{Op: OpRemove, Path: "/foo"},
+			},
+		},
+		// add folded with replace
+		{
+			Patch{
+				{Op: OpAdd, Path: "/foo", Value: 1},
+				{Op: OpReplace, Path: "/foo", Value: 2},
+				{Op: OpReplace, Path: "/foo", Value: 3},
+			},
+			Patch{
+				{Op: OpAdd, Path: "/foo", Value: 3},
+			},
+		},
+		// add cancelled by remove, if preceded by a remove
+		{
+			Patch{
+				{Op: OpRemove, Path: "/foo"},
+				{Op: OpAdd, Path: "/foo", Value: 1},
+				{Op: OpAdd, Path: "/bar", Value: 1},
+				{Op: OpRemove, Path: "/foo"},
+			},
+			Patch{
+				{Op: OpRemove, Path: "/foo"},
+				{Op: OpAdd, Path: "/bar", Value: 1},
+			},
+		},
+		{
+			Patch{
+				{Op: OpAdd, Path: "/foo", Value: 1},
+				{Op: OpAdd, Path: "/bar", Value: 1},
+				{Op: OpRemove, Path: "/foo"},
+			},
+			Patch{
+				{Op: OpAdd, Path: "/foo", Value: 1},
+				{Op: OpAdd, Path: "/bar", Value: 1},
+				{Op: OpRemove, Path: "/foo"},
+			},
+		},
+		// overlapping operations in between
+		{
+			Patch{
+				{Op: OpReplace, Path: "/foo", Value: 1},
+				{Op: OpTest, Path: "/foo", Value: 1},
+				{Op: OpReplace, Path: "/foo", Value: 2},
+			},
+			Patch{
+				{Op: OpReplace, Path: "/foo", Value: 1},
+				{Op: OpTest, Path: "/foo", Value: 1},
+				{Op: OpReplace, Path: "/foo", Value: 2},
+			},
+		},
+		{
+			Patch{
+				{Op: OpAdd, Path: "/foo", Value: map[string]interface{}{}},
+				{Op: OpAdd, Path: "/foo/bar", Value: 1},
+				{Op: OpRemove, Path: "/foo"},
+			},
+			Patch{
+				{Op: OpAdd, Path: "/foo", Value: map[string]interface{}{}},
+				{Op: OpAdd, Path: "/foo/bar", Value: 1},
+				{Op: OpRemove, Path: "/foo"},
+			},
+		},
+		{
+			Patch{
+				{Op: OpAdd, Path: "/arr/1", Value: 1},
+				{Op: OpAdd, Path: "/arr/0", Value: 2},
+				{Op: OpRemove, Path: "/arr/1"},
+			},
+			Patch{
+				{Op: OpAdd, Path: "/arr/1", Value: 1},
+				{Op: OpAdd, Path: "/arr/0", Value: 2},
+				{Op: OpRemove, Path: "/arr/1"},
+			},
+		},
+		{
+			Patch{
+				{Op: OpReplace, Path: "/foo", Value: 1},
+				{Op: OpCopy, From: "/foo", Path: "/bar"},
+				{Op: OpReplace, Path: "/foo", Value: 2},
+			},
+			Patch{
+				{Op: OpReplace, Path: "/foo", Value: 1},
+				{Op: OpCopy, From: "/foo", Path: "/bar"},
+				{Op: OpReplace, Path: "/foo", Value: 2},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		got := c.patch.Optimize()
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%d: value mismatch, expected: %#v, got: %#v", i, c.expect, got)
+		}
+	}
+
+	// optimized patches have the same effect, including failures
+	effects := []struct {
+		doc   string
+		patch Patch
+	}{
+		// add overwrites an existing member
+		{`{"foo":0}`, Patch{{Op: OpAdd, Path: "/foo", Value: 1}, {Op: OpRemove, Path: "/foo"}}},
+		// add fails due to a missing parent
+		{`{}`, Patch{{Op: OpAdd, Path: "/missing/foo", Value: 1}, {Op: OpRemove, Path: "/missing/foo"}}},
+		// remove fails for the end of an array
+		{`{"arr":[]}`, Patch{{Op: OpAdd, Path: "/arr/-", Value: 1}, {Op: OpRemove, Path: "/arr/-"}}},
+		// add restores a removed member
+		{`{"foo":0,"bar":1}`, Patch{{Op: OpRemove, Path: "/foo"}, {Op: OpAdd, Path: "/foo", Value: 1}, {Op: OpRemove, Path: "/foo"}}},
+		{`{"arr":[1,2]}`, Patch{{Op: OpRemove, Path: "/arr/0"}, {Op: OpAdd, Path: "/arr/0", Value: 3}, {Op: OpRemove, Path: "/arr/0"}}},
+	}
+	for i, c := range effects {
+		var doc interface{}
+		json.Unmarshal([]byte(c.doc), &doc)
+		raw, _ := json.Marshal(c.patch)
+		expect, expectErr := ApplyPatch(doc, raw)
+		raw, _ = json.Marshal(c.patch.Optimize())
+		got, err := ApplyPatch(doc, raw)
+		if (err == nil) != (expectErr == nil) || !reflect.DeepEqual(got, expect) {
+			t.Errorf("%d: effect mismatch, expected: %#v (%v), got: %#v (%v)", i, expect, expectErr, got, err)
+		}
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	cases := []struct {
+		name   string
+		doc    string
+		patch  string
+		expect string
+		err    string
+	}{
+		{
+			// example from RFC 6902, section 3
+			"rfc example",
+			`{"a":{"b":{"c":"foo"}}}`,
+			`[
+				{"op": "test", "path": "/a/b/c", "value": "foo"},
+				{"op": "remove", "path": "/a/b/c"},
+				{"op": "add", "path": "/a/b/c", "value": ["foo", "bar"]},
+				{"op": "replace", "path": "/a/b/c", "value": 42},
+				{"op": "move", "from": "/a/b/c", "path": "/a/b/d"},
+				{"op": "copy", "from": "/a/b/d", "path": "/a/b/e"}
+			]`,
+			`{"a":{"b":{"d":42,"e":42}}}`,
+			"",
+		},
+		{
+			"array operations",
+			`{"foo":["bar","baz"]}`,
+			`[
+				{"op": "add", "path": "/foo/1", "value": "qux"},
+				{"op": "remove", "path": "/foo/0"},
+				{"op": "replace", "path": "/foo/1", "value": "boo"},
+				{"op": "add", "path": "/foo/-", "value": null}
+			]`,
+			`{"foo":["qux","boo",null]}`,
+			"",
+		},
+		{
+			"replace root",
+			`{"foo":"bar"}`,
+			`[{"op": "replace", "path": "", "value": {"baz": 1}}]`,
+			`{"baz":1}`,
+			"",
+		},
+		{
+			"replace root with another type",
+			`{"foo":"bar"}`,
+			`[{"op": "replace", "path": "", "value": [1, 2]}, {"op": "add", "path": "/-", "value": 3}, {"op": "remove", "path": "/0"}]`,
+			`[2,3]`,
+			"",
+		},
+		{
+			"add root with another type",
+			`["a"]`,
+			`[{"op": "add", "path": "", "value": "b"}]`,
+			`"b"`,
+			"",
+		},
+		{
+			"failing test",
+			`{"baz":"qux"}`,
+			`[{"op": "add", "path": "/foo", "value": 1}, {"op": "test", "path": "/baz", "value": "bar"}]`,
+			"",
+			"operation 1: test: value at '/baz' does not match",
+		},
+		{
+			"remove missing",
+			`{"foo":"bar"}`,
+			`[{"op": "remove", "path": "/baz"}]`,
+			"",
+			"operation 0: get: map has no key 'baz'",
+		},
+		{
+			"add to missing parent",
+			`{"foo":"bar"}`,
+			`[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			"",
+			"operation 0: get: map has no key 'baz'",
+		},
+		{
+			"unknown op",
+			`{}`,
+			`[{"op": "test", "path": "", "value": {}}, {"op": "frob", "path": "/foo"}]`,
+			"",
+			"operation 1: invalid patch: unknown operation 'frob'",
+		},
+		{"missing op", `{}`, `[{"path": "/foo"}]`, "", "operation 0: invalid patch: missing 'op'"},
+		{"missing path", `{}`, `[{"op": "add", "value": 1}]`, "", "operation 0: invalid patch: missing 'path'"},
+		{"missing from", `{}`, `[{"op": "copy", "path": "/foo"}]`, "", "operation 0: invalid patch: missing 'from'"},
+		{"missing value", `{}`, `[{"op": "add", "path": "/foo"}]`, "", "operation 0: invalid patch: missing 'value'"},
+		{"invalid path", `{}`, `[{"op": "add", "path": "/a~2", "value": 1}]`, "", "operation 0: path '/a~2': invalid pointer: invalid escape sequence at offset 1 in token 'a~2'"},
+		{"invalid patch", `{}`, `{"op": "add"}`, "", "invalid patch: json: cannot unmarshal object into Go value of type []jsonpointer.rawOperation"},
+	}
+
+	for _, c := range cases {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(c.doc), &doc); err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		got, err := ApplyPatch(doc, []byte(c.patch))
+
+		// the original document is never modified
+		var orig interface{}
+		json.Unmarshal([]byte(c.doc), &orig)
+		if !reflect.DeepEqual(doc, orig) {
+			t.Errorf("%s: document was modified: %#v", c.name, doc)
+		}
+		if assertError(t, c.name, err, c.err) {
+			continue
+		}
+		if gotJSON, _ := json.Marshal(got); string(gotJSON) != c.expect {
+			t.Errorf("%s: value mismatch, expected: %s, got: %s", c.name, c.expect, gotJSON)
+		}
+	}
+
+	// malformed patches are reported as invalid patch errors, invalid paths
+	// as invalid pointer errors
+	for _, c := range []struct {
+		patch    string
+		sentinel error
+	}{
+		{`{"op": "add"}`, ErrPatchInvalid},
+		{`[{"path": "/foo"}]`, ErrPatchInvalid},
+		{`[{"op": "frob", "path": "/foo"}]`, ErrPatchInvalid},
+		{`[{"op": "add", "value": 1}]`, ErrPatchInvalid},
+		{`[{"op": "copy", "path": "/foo"}]`, ErrPatchInvalid},
+		{`[{"op": "add", "path": "/foo"}]`, ErrPatchInvalid},
+		{`[{"op": "add", "path": "/a~2", "value": 1}]`, ErrParseFailed},
+		{`[{"op": "copy", "from": "/a~2", "path": "/foo"}]`, ErrParseFailed},
+	} {
+		_, err := ApplyPatch(map[string]interface{}{}, []byte(c.patch))
+		var perr *Error
+		if !errors.Is(err, c.sentinel) || !errors.As(err, &perr) {
+			t.Errorf("%s: expected error to match %q, got: %v", c.patch, c.sentinel, err)
+		}
+		for _, other := range []error{ErrPatchInvalid, ErrParseFailed} {
+			if other != c.sentinel && errors.Is(err, other) {
+				t.Errorf("%s: expected error not to match %q", c.patch, other)
+			}
+		}
+	}
+}
+
+func TestApplyPatchStruct(t *testing.T) {
+	type Doc struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	doc := Doc{Name: "foo", Tags: []string{"a"}}
+
+	got, err := ApplyPatch(doc, []byte(`[
+		{"op": "replace", "path": "/name", "value": "bar"},
+		{"op": "add", "path": "/tags/0", "value": "b"},
+		{"op": "test", "path": "/tags", "value": ["b", "a"]}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expect := Doc{Name: "bar", Tags: []string{"b", "a"}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("value mismatch, expected: %#v, got: %#v", expect, got)
+	}
+	if doc.Name != "foo" || len(doc.Tags) != 1 {
+		t.Errorf("document was modified: %#v", doc)
+	}
+}
+
+func TestPointerTest(t *testing.T) {
+	type Item struct {
+		B int    `json:"b"`
+		A string `json:"a"`
+	}
+	doc := map[string]interface{}{
+		"int":    1,
+		"float":  1.5,
+		"uint8":  uint8(7),
+		"str":    "foo",
+		"null":   nil,
+		"list":   []int{1, 2},
+		"object": map[string]interface{}{"a": "x", "b": 2.0},
+		"struct": Item{B: 2, A: "x"},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expected  interface{}
+		err       string
+	}{
+		{"/int", 1.0, ""},
+		{"/int", int64(1), ""},
+		{"/float", float32(1.5), ""},
+		{"/uint8", 7, ""},
+		{"/str", "foo", ""},
+		{"/null", nil, ""},
+		{"/list", []interface{}{1.0, 2.0}, ""},
+		{"/object", map[string]interface{}{"b": 2, "a": "x"}, ""},
+		{"/object", Item{B: 2, A: "x"}, ""},
+		{"/struct", map[string]interface{}{"a": "x", "b": 2}, ""},
+		{"/int", 2, "test: value at '/int' does not match"},
+		{"/int", "1", "test: value at '/int' does not match"},
+		{"/list", []int{2, 1}, "test: value at '/list' does not match"},
+		{"/object", map[string]interface{}{"a": "x"}, "test: value at '/object' does not match"},
+		{"/null", false, "test: value at '/null' does not match"},
+		{"/missing", 1, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		err := ptr.Test(doc, c.expected)
+		if strings.HasPrefix(c.err, "test:") && !errors.Is(err, ErrTestFailed) {
+			t.Errorf("%s: expected error to match ErrTestFailed", c.ptrstring)
+		}
+		assertError(t, c.ptrstring, err, c.err)
+	}
+}
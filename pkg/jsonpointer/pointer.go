@@ -7,48 +7,132 @@
 package jsonpointer
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Pointer represents a parsed JSON pointer
 type Pointer []string
 
+// Optional is implemented by wrappers of optional values. If enabled with
+// Resolver.UnwrapOptionals, such wrappers are resolved transparently.
+type Optional interface {
+	// Get returns the wrapped value and whether it is present.
+	Get() (interface{}, bool)
+}
+
+// Indexable is implemented by custom array-like containers. Documents that
+// implement it are resolved using its methods instead of reflection.
+type Indexable interface {
+	// Len returns the number of elements.
+	Len() int
+	// Index returns the element at the given index.
+	Index(int) interface{}
+}
+
+// Keyable is implemented by custom map-like containers. Documents that
+// implement it are resolved using its methods instead of reflection.
+type Keyable interface {
+	// Value returns the value for the given key and whether the key exists.
+	Value(string) (interface{}, bool)
+}
+
 // New creates a new JSON pointer from string, *url.URL or another Pointer. If a
 // string is given and that string contains an URL, it will use the URL's
 // fragment as the pointer (the bit after the '#' symbol).
 func New(val interface{}) (Pointer, error) {
-	switch v := val.(type) {
-	case Pointer:
-		newPtr := make([]string, len(v))
-		copy(newPtr, v)
-		return newPtr, nil
+	return defaultDialect.New(val)
+}
 
-	case string:
-		// fast paths that skip url parse step
-		if len(v) == 0 || v == "#" {
-			return Pointer{}, nil
-		} else if v[0] == '/' {
-			return parse(v)
-		}
+// NewStrict creates a new JSON pointer from string like New, but rejects
+// tokens with malformed escape sequences. RFC 6901 only permits "~0" and "~1",
+// whereas New leaves any other "~" untouched.
+func NewStrict(str string) (Pointer, error) {
+	return strictDialect.New(str)
+}
 
-		u, err := url.Parse(v)
-		if err != nil {
-			uerr := err.(*url.Error)
-			return nil, wrapError(uerr.Err, ErrInvalidJSONPointer, "failed to parse URL: %s", uerr.Err)
+// ValidateAll parses each of the given pointer strings with strict validation.
+// Instead of stopping at the first malformed pointer, it returns a single error
+// that aggregates all failures together with their input strings.
+func ValidateAll(ss []string) error {
+	var errs []error
+	for _, s := range ss {
+		if _, err := strictDialect.New(s); err != nil {
+			errs = append(errs, fmt.Errorf("'%s': %w", s, err))
 		}
-		return parse(u.Fragment)
+	}
+	return errors.Join(errs...)
+}
 
-	case *url.URL:
-		return parse(v.Fragment)
+// FieldPointer returns a single-token pointer that addresses the field with the
+// given Go name of a struct type. The struct type can be given as a struct
+// value, a pointer to a struct or a reflect.Type. The token is the name of the
+// field's json tag if present, else the Go field name. Fields of embedded
+// structs are considered as well. Fields tagged with "-" cannot be addressed.
+func FieldPointer(structType interface{}, fieldName string) (Pointer, error) {
+	st, ok := structType.(reflect.Type)
+	if !ok {
+		st = reflect.TypeOf(structType)
+	}
+	for st != nil && st.Kind() == reflect.Pointer {
+		st = st.Elem()
+	}
+	if st == nil || st.Kind() != reflect.Struct {
+		return nil, newError(ErrInvalidJSONPointer, "invalid struct type: %v", st)
+	}
 
-	default:
-		return nil, newError(ErrInvalidJSONPointer, "invalid value for pointer: %T", v)
+	sf, ok := st.FieldByName(fieldName)
+	if !ok {
+		return nil, newError(ErrInvalidJSONPointer, "struct %s has no field '%s'", st, fieldName)
+	}
+	if sf.Tag.Get(defaultTagName) == "-" {
+		return nil, newError(ErrInvalidJSONPointer, "field '%s' of struct %s is excluded by its tag", fieldName, st)
+	}
+	if name := tagName(sf, defaultTagName); name != "" {
+		return Pointer{name}, nil
 	}
+	return Pointer{sf.Name}, nil
+}
+
+// FromMixedPath creates a new JSON pointer from a path of mixed map keys and
+// array indices, such as []interface{}{"foo", 0, "bar"}. Strings are used as
+// tokens as they are and integers are converted to their decimal form.
+func FromMixedPath(path []interface{}) (Pointer, error) {
+	newPtr := make(Pointer, 0, len(path))
+	for _, elm := range path {
+		switch e := elm.(type) {
+		case string:
+			newPtr = append(newPtr, e)
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			newPtr = append(newPtr, fmt.Sprint(e))
+		default:
+			return nil, newError(ErrInvalidJSONPointer, "invalid path element: %T", e)
+		}
+	}
+	return newPtr, nil
+}
+
+// MixedPath returns the pointer as a path of mixed map keys and array indices.
+// Tokens that are array indices, i.e. non-negative integers without leading
+// zeros, are returned as int and all other tokens as string.
+func (p Pointer) MixedPath() []interface{} {
+	path := make([]interface{}, len(p))
+	for i, tok := range p {
+		if idx, err := strconv.Atoi(tok); err == nil && idx >= 0 && strconv.Itoa(idx) == tok {
+			path[i] = idx
+		} else {
+			path[i] = tok
+		}
+	}
+	return path
 }
 
 // String returns a string representation of the pointer.
@@ -63,6 +147,48 @@ func (p Pointer) String() (str string) {
 	return "/" + strings.Join(escapedTokens, "/")
 }
 
+// MarshalJSON encodes the pointer as JSON string in its RFC 6901 string
+// representation, e.g. "/foo/bar". The empty pointer is encoded as "".
+func (p Pointer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes a pointer from a JSON string in its RFC 6901 string
+// representation, see UnmarshalText.
+func (p *Pointer) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return wrapError(err, ErrInvalidJSONPointer, "pointer must be a JSON string: %s", err)
+	}
+	return p.UnmarshalText([]byte(str))
+}
+
+// MarshalText encodes the pointer in its RFC 6901 string representation. It
+// implements encoding.TextMarshaler, so that pointers can be used by
+// text-based encoders.
+func (p Pointer) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a pointer from its RFC 6901 string representation. It
+// implements encoding.TextUnmarshaler. Unlike New, URI fragment identifiers
+// are not accepted.
+func (p *Pointer) UnmarshalText(text []byte) error {
+	newPtr, err := defaultDialect.parse(string(text))
+	if err != nil {
+		return err
+	}
+	*p = newPtr
+	return nil
+}
+
+// DisplayString returns the unescaped tokens of the pointer joined with the
+// delimiter. It is meant for human display only, e.g. in logs or breadcrumbs.
+// The result is not a valid JSON pointer, as tokens are not escaped.
+func (p Pointer) DisplayString(delim string) string {
+	return strings.Join(p, delim)
+}
+
 // IsEmpty indicates whether the pointer is empty.
 func (p Pointer) IsEmpty() bool {
 	return len(p) == 0
@@ -73,11 +199,56 @@ func (p Pointer) Parent() Pointer {
 	if p.IsEmpty() {
 		return Pointer{}
 	}
-	newPtr := make(Pointer, len(p))
-	copy(newPtr[:len(p)-1], p)
+	newPtr := make(Pointer, len(p)-1)
+	copy(newPtr, p)
 	return newPtr
 }
 
+// Leaf returns the last token of the pointer, which addresses the value within
+// its parent. It returns false if the pointer is empty.
+func (p Pointer) Leaf() (string, bool) {
+	if len(p) == 0 {
+		return "", false
+	}
+	return p[len(p)-1], true
+}
+
+// Truncate returns a copy of the pointer that only contains the first n tokens.
+// If n is less than or equal to zero, an empty pointer is returned.
+func (p Pointer) Truncate(n int) Pointer {
+	if n <= 0 {
+		return Pointer{}
+	}
+	if n > len(p) {
+		n = len(p)
+	}
+	newPtr := make(Pointer, n)
+	copy(newPtr, p)
+	return newPtr
+}
+
+// DivergeAt returns the index of the first token at which the pointer and the
+// other pointer differ. If one pointer is a prefix of the other, the length of
+// the shorter pointer is returned.
+func (p Pointer) DivergeAt(other Pointer) int {
+	n := len(p)
+	if len(other) < n {
+		n = len(other)
+	}
+	for i := 0; i < n; i++ {
+		if p[i] != other[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// HasPrefix reports whether the pointer starts with the tokens of the prefix,
+// i.e. whether the prefix addresses the same value or an ancestor.
+func (p Pointer) HasPrefix(prefix Pointer) bool {
+	return len(prefix) <= len(p) && p.DivergeAt(prefix) == len(prefix)
+}
+
 // Join joins a pointer with a string.
 func (p Pointer) Join(elems ...interface{}) (Pointer, error) {
 	newPtr := make([]string, len(p))
@@ -123,15 +294,13 @@ func (p Pointer) RelativeTo(other interface{}) (Pointer, error) {
 		return nil, fmt.Errorf("%s does not start with %s", p, otherPtr)
 	}
 
-	var numCmnParts int
 	for i, part := range otherPtr {
 		if p[i] != part {
 			return p, fmt.Errorf("%s does not start with %s", p, otherPtr)
 		}
-		numCmnParts = i
 	}
 
-	cmnParts := p[numCmnParts+1:]
+	cmnParts := p[len(otherPtr):]
 	newPtr := make([]string, len(cmnParts))
 	copy(newPtr, cmnParts)
 	return newPtr, nil
@@ -139,47 +308,341 @@ func (p Pointer) RelativeTo(other interface{}) (Pointer, error) {
 
 // Get returns the value from the given document that the pointer points to.
 func (p Pointer) Get(doc interface{}) (interface{}, error) {
-	var err error
+	return defaultResolver.Get(p, doc)
+}
+
+// Resolve returns the value that the pointer points to as reflect.Value. See
+// Resolver.Resolve.
+func (p Pointer) Resolve(doc interface{}) (reflect.Value, error) {
+	return defaultResolver.Resolve(p, doc)
+}
+
+// GetWithPath is like Get, but additionally returns the canonical string
+// representation of the pointer, e.g. for logging.
+func (p Pointer) GetWithPath(doc interface{}) (value interface{}, path string, err error) {
+	value, err = p.Get(doc)
+	return value, p.String(), err
+}
+
+// GetMap resolves all pointers against the document and returns a map from the
+// string representation of each resolvable pointer to its value. The returned
+// errors are parallel to the pointers, i.e. the error at index i is the error
+// of resolving ptrs[i], or nil if it resolved.
+func GetMap(doc interface{}, ptrs []Pointer) (map[string]interface{}, []error) {
+	values := make(map[string]interface{}, len(ptrs))
+	errs := make([]error, len(ptrs))
+	for i, p := range ptrs {
+		value, err := p.Get(doc)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		values[p.String()] = value
+	}
+	return values, errs
+}
+
+// Probe checks whether the pointer can be resolved against the document. See
+// Resolver.Probe.
+func (p Pointer) Probe(doc interface{}) error {
+	return defaultResolver.Probe(p, doc)
+}
+
+// Exists reports whether the pointer can be resolved against the document. See
+// Resolver.Exists.
+func (p Pointer) Exists(doc interface{}) bool {
+	return defaultResolver.Exists(p, doc)
+}
+
+// GetOrSchemaDefault returns the value from the document that the pointer
+// points to. If the value is absent, the default value at the same location in
+// the schema document is returned instead, where the schema mirrors the shape
+// of the document with default values. If neither resolves, the error of the
+// document resolution is returned.
+func (p Pointer) GetOrSchemaDefault(doc interface{}, schema interface{}) (interface{}, error) {
+	value, err := p.Get(doc)
+	if err == nil || !errors.Is(err, ErrGetFailed) {
+		return value, err
+	}
+	if def, schemaErr := p.Get(schema); schemaErr == nil {
+		return def, nil
+	}
+	return nil, err
+}
+
+// GetTraced is like Get, but invokes trace before resolving each token of the
+// pointer with the kind of the current container. See Resolver.GetTraced.
+func (p Pointer) GetTraced(doc interface{}, trace func(tokenIndex int, token string, kind reflect.Kind)) (interface{}, error) {
+	return defaultResolver.GetTraced(p, doc, trace)
+}
+
+// ResolveDeepest descends into the document as far as possible. It returns the
+// deepest resolvable value, the prefix of the pointer that reached it and the
+// error that occurred when resolving the next token. If the pointer can be
+// resolved completely, reached equals the pointer and err is nil.
+func (p Pointer) ResolveDeepest(doc interface{}) (value interface{}, reached Pointer, err error) {
 	resultVal := reflect.ValueOf(doc)
-	for _, part := range p {
-		if resultVal, err = getValue(resultVal, part); err != nil {
-			return nil, err
+	for i, part := range p {
+		nextVal, err := defaultResolver.getValue(resultVal, part)
+		if err != nil {
+			if resultVal.CanInterface() {
+				value = resultVal.Interface()
+			}
+			return value, p.Truncate(i), err
 		}
+		resultVal = nextVal
 	}
 	if !resultVal.CanInterface() {
-		return nil, newError(ErrGet, "cannot get document value")
+		return nil, p.Truncate(len(p)), newError(ErrGet, "cannot get document value")
 	}
-	return resultVal.Interface(), nil
+	return resultVal.Interface(), p.Truncate(len(p)), nil
 }
 
-// Set sets the value at the given pointer in the given document.
-func (p Pointer) Set(doc interface{}, value interface{}) (err error) {
-	// get the value in the document we want to set
-	docVal := reflect.ValueOf(doc)
+// SplitAtResolvable splits the pointer into the longest prefix that can be
+// resolved against the document and the remaining tokens. It also returns the
+// value at the end of the resolvable prefix and the error that prevented the
+// resolution of the remainder. If the pointer can be resolved completely, the
+// remainder is empty and err is nil.
+func (p Pointer) SplitAtResolvable(doc interface{}) (resolvable Pointer, value interface{}, remainder Pointer, err error) {
+	value, resolvable, err = p.ResolveDeepest(doc)
+	remainder = make(Pointer, len(p)-len(resolvable))
+	copy(remainder, p[len(resolvable):])
+	return resolvable, value, remainder, err
+}
+
+// GetWithParent returns the value that the pointer points to together with its
+// parent container and the last token of the pointer, which addresses the
+// value within the parent. For the empty pointer, the parent is nil and the
+// token is empty.
+func (p Pointer) GetWithParent(doc interface{}) (value interface{}, parent interface{}, lastToken string, err error) {
+	if len(p) == 0 {
+		return doc, nil, "", nil
+	}
+	err = p.RangeDescent(doc, func(container interface{}, token string, child interface{}) error {
+		parent, lastToken, value = container, token, child
+		return nil
+	})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return value, parent, lastToken, nil
+}
+
+// GetMutable returns a deep copy of the value that the pointer points to
+// together with a commit function, which writes a value back to the same
+// location in the document using Set. This allows to safely modify the value
+// and store it back. Committing fails if the value in the document cannot be
+// resolved anymore or its type has changed in the meantime.
+func (p Pointer) GetMutable(doc interface{}) (value interface{}, commit func(interface{}) error, err error) {
+	value, err = p.Get(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	typ := reflect.TypeOf(value)
+	commit = func(newValue interface{}) error {
+		cur, err := p.Get(doc)
+		if err != nil {
+			return wrapError(err, ErrSet, "document has changed: %s", err)
+		}
+		if curType := reflect.TypeOf(cur); curType != typ {
+			return newError(ErrSet, "document has changed: expected value of type %s, got %s", typ, curType)
+		}
+		return p.Set(doc, newValue)
+	}
+	if value != nil {
+		value = deepCopy(reflect.ValueOf(value)).Interface()
+	}
+	return value, commit, nil
+}
+
+// ResolveField is like Get, but if the pointer points to a struct field, it
+// additionally returns the field's StructField, which includes its tag, and ok
+// is set to true. For values that are not struct fields, ok is false.
+func (p Pointer) ResolveField(doc interface{}) (value interface{}, field reflect.StructField, ok bool, err error) {
+	value, parent, lastToken, err := p.GetWithParent(doc)
+	if err != nil {
+		return nil, reflect.StructField{}, false, err
+	}
+	if parentVal := indirect(reflect.ValueOf(parent)); parentVal.Kind() == reflect.Struct {
+		field, ok = findField(parentVal.Type(), lastToken, defaultTagName)
+	}
+	return value, field, ok, nil
+}
+
+// RangeDescent resolves the pointer against the document and calls fn for each
+// descent step with the current container, the token and the child value the
+// token resolves to. It stops at the first error, either returned by fn or
+// occurred during resolution.
+func (p Pointer) RangeDescent(doc interface{}, fn func(container interface{}, token string, child interface{}) error) error {
+	resultVal := reflect.ValueOf(doc)
 	for _, part := range p {
-		if docVal, err = getValue(docVal, part); err != nil {
+		childVal, err := defaultResolver.getValue(resultVal, part)
+		if err != nil {
+			return err
+		}
+		if !resultVal.CanInterface() || !childVal.CanInterface() {
+			return newError(ErrGet, "cannot get document value")
+		}
+		if err := fn(resultVal.Interface(), part, childVal.Interface()); err != nil {
 			return err
 		}
+		resultVal = childVal
+	}
+	return nil
+}
+
+// ValidateForType checks whether the pointer could be used to set a value in a
+// document of the given type, without requiring an actual document. It walks
+// the structure of the type and returns an error describing the first token
+// that cannot be resolved. Interface types are assumed to hold any value, so
+// tokens following an interface are not checked.
+func (p Pointer) ValidateForType(t reflect.Type) error {
+	for _, part := range p {
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil {
+			return newError(ErrSet, "invalid type")
+		}
+
+		switch t.Kind() {
+		case reflect.Interface:
+			return nil
+
+		case reflect.Array, reflect.Slice:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 {
+				return newError(ErrSet, "invalid array index: %s", part)
+			}
+			if t.Kind() == reflect.Array && i >= t.Len() {
+				return newError(ErrSet, "index %d exceeds array length of %d", i, t.Len())
+			}
+			t = t.Elem()
+
+		case reflect.Map:
+			if _, err := convertKey(t.Key(), part, ErrSet); err != nil {
+				return err
+			}
+			t = t.Elem()
+
+		case reflect.Struct:
+			sf, ok := findField(t, part, defaultTagName)
+			if !ok {
+				return newError(ErrSet, "struct %s has no field '%s'", t, part)
+			}
+			if !sf.IsExported() {
+				return newError(ErrSet, "struct field '%s' of %s is unexported", part, t)
+			}
+			t = sf.Type
+
+		default:
+			return newError(ErrSet, "cannot resolve token '%s' in primitive type %s", part, t)
+		}
 	}
+	return nil
+}
 
-	// set value to pointer
-	return setValue(docVal, value)
+// Set sets the value at the given pointer in the given document.
+func (p Pointer) Set(doc interface{}, value interface{}) error {
+	return defaultResolver.Set(p, doc, value)
 }
 
-func setValue(doc reflect.Value, value interface{}) error {
+// SetRecursive sets the value at the given pointer in the given document like
+// Set, but creates missing intermediate values of maps with interface or map
+// element type (e.g. map[string]interface{} or map[string]map[string]int).
+// Missing map elements are created as an empty map of the element type, or as
+// map[string]interface{} for interface element types that it implements. The
+// value itself is stored in a map as well, even if the key does not exist yet.
+func (p Pointer) SetRecursive(doc interface{}, value interface{}) error {
+	docVal := reflect.ValueOf(doc)
+	for i, part := range p {
+		mapVal := indirect(docVal)
+		switch {
+		case mapVal.Kind() == reflect.Map && (mapVal.Type().Elem().Kind() == reflect.Interface || mapVal.Type().Elem().Kind() == reflect.Map || i == len(p)-1):
+			// create missing values below
+		case mapVal.Kind() == reflect.Slice || mapVal.Kind() == reflect.Array || mapVal.Kind() == reflect.Struct || mapVal.Kind() == reflect.Map:
+			_, err := defaultResolver.set(docVal, p, i, value)
+			return err
+		case !mapVal.IsValid():
+			return newError(ErrSet, "document value is nil")
+		default:
+			return newError(ErrSet, "cannot create key '%s' in primitive value of type %s", part, mapVal.Type())
+		}
+
+		if mapVal.IsNil() {
+			return newError(ErrSet, "cannot create key '%s' in nil map", part)
+		}
+		keyVal, err := mapKey(mapVal, part)
+		if err != nil {
+			return err
+		}
+		if i == len(p)-1 && mapVal.Type().Elem().Kind() != reflect.Interface {
+			elemVal := reflect.New(mapVal.Type().Elem()).Elem()
+			if err := defaultResolver.setValue(elemVal, value); err != nil {
+				return err
+			}
+			mapVal.SetMapIndex(keyVal, elemVal)
+			return nil
+		}
+		if i == len(p)-1 {
+			valVal := reflect.ValueOf(value)
+			if !valVal.IsValid() {
+				valVal = reflect.Zero(mapVal.Type().Elem())
+			} else if !valVal.Type().AssignableTo(mapVal.Type().Elem()) {
+				return newError(ErrSet, "cannot set map value of type %s to value of type %s", mapVal.Type().Elem(), valVal.Type())
+			}
+			mapVal.SetMapIndex(keyVal, valVal)
+			return nil
+		}
+		childVal := mapVal.MapIndex(keyVal)
+		if !childVal.IsValid() || isNil(childVal) {
+			elemType := mapVal.Type().Elem()
+			switch {
+			case elemType.Kind() == reflect.Map:
+				childVal = reflect.MakeMap(elemType)
+			case genericMapType.AssignableTo(elemType):
+				childVal = reflect.ValueOf(map[string]interface{}{})
+			default:
+				return newError(ErrSet, "cannot create value of type %s for key '%s'", elemType, part)
+			}
+			mapVal.SetMapIndex(keyVal, childVal)
+		}
+		docVal = childVal
+	}
+	_, err := defaultResolver.set(docVal, p, len(p), value)
+	return err
+}
+
+// genericMapType is the type of maps created by SetRecursive for interface
+// element types.
+var genericMapType = reflect.TypeOf(map[string]interface{}{})
+
+func (r *Resolver) setValue(doc reflect.Value, value interface{}) error {
+	if doc.Kind() == reflect.Interface && doc.CanSet() {
+		srcVal := reflect.ValueOf(value)
+		if !srcVal.IsValid() {
+			doc.Set(reflect.Zero(doc.Type()))
+			return nil
+		}
+		if !srcVal.Type().AssignableTo(doc.Type()) {
+			return newError(ErrSet, "value of type %s does not implement %s", srcVal.Type(), doc.Type())
+		}
+		doc.Set(srcVal)
+		return nil
+	}
 	if doc.Kind() == reflect.Interface {
 		doc = doc.Elem()
 	}
 	if !doc.IsValid() {
-		return errors.New("cannot set value on invalid document")
+		return newError(ErrSet, "cannot set value on invalid document")
 	}
 	if !doc.CanSet() {
-		return errors.New("cannot set value on unaddressable document or unexported field")
+		return newError(ErrSet, "cannot set value on unaddressable document or unexported field")
 	}
 
 	srcVal := reflect.ValueOf(value)
 	if !srcVal.IsValid() {
-		return errors.New("cannot set value on invalid value")
+		return newError(ErrSet, "cannot set value on invalid value")
 	}
 	indSrcVal := indirect(srcVal)
 
@@ -188,7 +651,7 @@ func setValue(doc reflect.Value, value interface{}) error {
 	// Pointer, Array, Slice, Map, Struct
 	// -------------------------------------------------------------------------
 	case reflect.Pointer, reflect.Array, reflect.Slice, reflect.Map, reflect.Struct:
-		if doc.Type() != srcVal.Type() {
+		if !srcVal.Type().AssignableTo(doc.Type()) {
 			return newError(ErrSet, "cannot set document value of type %s to value of type %s", doc.Type(), srcVal.Type())
 		}
 		doc.Set(srcVal)
@@ -206,9 +669,17 @@ func setValue(doc reflect.Value, value interface{}) error {
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 			doc.SetString(strconv.FormatUint(indSrcVal.Uint(), 10))
 		case reflect.Float32, reflect.Float64:
-			doc.SetString(strconv.FormatFloat(indSrcVal.Float(), 'f', -1, 64))
+			format, prec, err := r.floatFormat()
+			if err != nil {
+				return err
+			}
+			doc.SetString(strconv.FormatFloat(indSrcVal.Float(), format, prec, 64))
 		case reflect.Complex64, reflect.Complex128:
-			doc.SetString(strconv.FormatComplex(indSrcVal.Complex(), 'f', -1, 128))
+			format, prec, err := r.floatFormat()
+			if err != nil {
+				return err
+			}
+			doc.SetString(strconv.FormatComplex(indSrcVal.Complex(), format, prec, 128))
 		case reflect.Bool:
 			if indSrcVal.Bool() {
 				doc.SetString("true")
@@ -224,90 +695,107 @@ func setValue(doc reflect.Value, value interface{}) error {
 	// Int, Int8, Int16, Int32, Int64
 	// -------------------------------------------------------------------------
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
 		switch indSrcVal.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			doc.SetInt(indSrcVal.Int())
+			i = indSrcVal.Int()
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			doc.SetInt(int64(indSrcVal.Uint()))
-		case reflect.Float32, reflect.Float64:
-			doc.SetInt(int64(indSrcVal.Float()))
-		case reflect.Complex64, reflect.Complex128:
-			doc.SetInt(int64(real(indSrcVal.Complex())))
+			if r.CheckNumericRange && indSrcVal.Uint() > math.MaxInt64 {
+				return outOfRangeError(indSrcVal, doc)
+			}
+			i = int64(indSrcVal.Uint())
+		case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+			f := realValue(indSrcVal)
+			if r.CheckNumericRange && !(f >= math.MinInt64 && f < math.MaxInt64) {
+				return outOfRangeError(indSrcVal, doc)
+			}
+			i = int64(f)
 		case reflect.Bool:
 			if indSrcVal.Bool() {
-				doc.SetInt(1)
-			} else {
-				doc.SetInt(0)
+				i = 1
 			}
 		case reflect.String:
-			i, err := strconv.ParseInt(indSrcVal.String(), 10, 64)
+			var err error
+			i, err = strconv.ParseInt(indSrcVal.String(), 10, 64)
 			if err != nil {
 				return newError(ErrSet, "conversion failed (string ➜ int)")
 			}
-			doc.SetInt(i)
 		default:
 			return newError(ErrSet, "type mismatch (%s ➜ %s)", indSrcVal.Kind(), doc.Kind())
 		}
+		if r.CheckNumericRange && doc.OverflowInt(i) {
+			return outOfRangeError(indSrcVal, doc)
+		}
+		doc.SetInt(i)
 		return nil
 
 	// -------------------------------------------------------------------------
 	// Uint, Uint8, Uint16, Uint32, Uint64
 	// -------------------------------------------------------------------------
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
 		switch indSrcVal.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			doc.SetUint(uint64(indSrcVal.Int()))
+			if r.CheckNumericRange && indSrcVal.Int() < 0 {
+				return outOfRangeError(indSrcVal, doc)
+			}
+			u = uint64(indSrcVal.Int())
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			doc.SetUint(indSrcVal.Uint())
-		case reflect.Float32, reflect.Float64:
-			doc.SetUint(uint64(indSrcVal.Float()))
-		case reflect.Complex64, reflect.Complex128:
-			doc.SetUint(uint64(real(indSrcVal.Complex())))
+			u = indSrcVal.Uint()
+		case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+			f := realValue(indSrcVal)
+			if r.CheckNumericRange && !(f >= 0 && f < math.MaxUint64) {
+				return outOfRangeError(indSrcVal, doc)
+			}
+			u = uint64(f)
 		case reflect.Bool:
 			if indSrcVal.Bool() {
-				doc.SetUint(1)
-			} else {
-				doc.SetUint(0)
+				u = 1
 			}
 		case reflect.String:
-			i, err := strconv.ParseUint(indSrcVal.String(), 10, 64)
+			var err error
+			u, err = strconv.ParseUint(indSrcVal.String(), 10, 64)
 			if err != nil {
 				return newError(ErrSet, "conversion failed (string ➜ uint)")
 			}
-			doc.SetUint(i)
 		default:
 			return newError(ErrSet, "type mismatch (%s ➜ %s)", indSrcVal.Kind(), doc.Kind())
 		}
+		if r.CheckNumericRange && doc.OverflowUint(u) {
+			return outOfRangeError(indSrcVal, doc)
+		}
+		doc.SetUint(u)
 		return nil
 
 	// -------------------------------------------------------------------------
 	// Float32, Float64
 	// -------------------------------------------------------------------------
 	case reflect.Float32, reflect.Float64:
+		var f float64
 		switch indSrcVal.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			doc.SetFloat(float64(indSrcVal.Int()))
+			f = float64(indSrcVal.Int())
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			doc.SetFloat(float64(indSrcVal.Uint()))
-		case reflect.Float32, reflect.Float64:
-			doc.SetFloat(indSrcVal.Float())
-		case reflect.Complex64, reflect.Complex128:
-			doc.SetFloat(float64(real(indSrcVal.Complex())))
+			f = float64(indSrcVal.Uint())
+		case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+			f = realValue(indSrcVal)
 		case reflect.Bool:
 			if indSrcVal.Bool() {
-				doc.SetFloat(1)
-			} else {
-				doc.SetFloat(0)
+				f = 1
 			}
 		case reflect.String:
-			f, err := strconv.ParseFloat(indSrcVal.String(), 64)
+			var err error
+			f, err = strconv.ParseFloat(indSrcVal.String(), 64)
 			if err != nil {
 				return newError(ErrSet, "conversion failed (string ➜ float)")
 			}
-			doc.SetFloat(f)
 		default:
 			return newError(ErrSet, "type mismatch (%s ➜ %s)", indSrcVal.Kind(), doc.Kind())
 		}
+		if r.CheckNumericRange && doc.OverflowFloat(f) {
+			return outOfRangeError(indSrcVal, doc)
+		}
+		doc.SetFloat(f)
 		return nil
 
 	// -------------------------------------------------------------------------
@@ -370,6 +858,87 @@ func setValue(doc reflect.Value, value interface{}) error {
 	return newError(ErrSet, "unsupported type (%s)", doc.Kind())
 }
 
+// realValue returns the real part of a float or complex value.
+func realValue(val reflect.Value) float64 {
+	if val.Kind() == reflect.Complex64 || val.Kind() == reflect.Complex128 {
+		return real(val.Complex())
+	}
+	return val.Float()
+}
+
+func outOfRangeError(src, doc reflect.Value) *Error {
+	return newError(ErrSet, "value out of range (%s ➜ %s)", src.Kind(), doc.Kind())
+}
+
+// asOptional returns the value as Optional, if it implements the interface.
+func asOptional(val reflect.Value) (Optional, bool) {
+	if !val.IsValid() || !val.CanInterface() || isNil(val) {
+		return nil, false
+	}
+	if o, ok := val.Interface().(Optional); ok {
+		return o, true
+	}
+	if val.CanAddr() {
+		if o, ok := val.Addr().Interface().(Optional); ok {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
+// asMarshaler returns the value as json.Marshaler, if it implements the
+// interface.
+func asMarshaler(val reflect.Value) (json.Marshaler, bool) {
+	if !val.IsValid() || !val.CanInterface() || isNil(val) {
+		return nil, false
+	}
+	if m, ok := val.Interface().(json.Marshaler); ok {
+		return m, true
+	}
+	if val.CanAddr() {
+		if m, ok := val.Addr().Interface().(json.Marshaler); ok {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// remarshal marshals the value to JSON and parses it again into generic Go
+// values.
+func remarshal(m json.Marshaler) (interface{}, error) {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return nil, wrapError(err, ErrGet, "failed to marshal document value: %s", err)
+	}
+	var val interface{}
+	if err := json.Unmarshal(data, &val); err != nil {
+		return nil, wrapError(err, ErrGet, "failed to parse marshaled document value: %s", err)
+	}
+	return val, nil
+}
+
+// checkMutable returns an error if the resolver would descend into the value
+// through json.Marshaler. Such values are resolved in a remarshaled copy, so
+// modifications would not reach the document.
+func (r *Resolver) checkMutable(doc reflect.Value) error {
+	if r.ResolveMarshalers {
+		if _, ok := asMarshaler(doc); ok {
+			return newError(ErrSet, "cannot modify value resolved through json.Marshaler")
+		}
+	}
+	return nil
+}
+
+// isNil reports whether the value is a nil pointer, interface, map, slice,
+// channel or function.
+func isNil(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return val.IsNil()
+	}
+	return false
+}
+
 func indirect(val reflect.Value) reflect.Value {
 	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
 		return indirect(val.Elem())
@@ -378,11 +947,63 @@ func indirect(val reflect.Value) reflect.Value {
 }
 
 // getValue returns the value for the given key from the given document.
-func getValue(doc reflect.Value, key string) (reflect.Value, error) {
+func (r *Resolver) getValue(doc reflect.Value, key string) (reflect.Value, error) {
 	if !doc.IsValid() {
 		return reflect.Value{}, newError(ErrGet, "document value is invalid")
 	}
 
+	if r.UnwrapOptionals {
+		if o, ok := asOptional(doc); ok {
+			val, present := o.Get()
+			if !present {
+				return reflect.Value{}, newError(ErrGet, "optional value is absent")
+			}
+			return r.getValue(reflect.ValueOf(val), key)
+		}
+	}
+
+	if r.ResolveMarshalers {
+		if m, ok := asMarshaler(doc); ok {
+			val, err := remarshal(m)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return r.getValue(reflect.ValueOf(val), key)
+		}
+	}
+
+	if r.LoadLazy {
+		if l, ok := asLoader(doc); ok {
+			val, err := load(l)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return r.getValue(val, key)
+		}
+	}
+
+	// custom containers take precedence over reflection
+	if doc.CanInterface() && !isNil(doc) {
+		switch d := doc.Interface().(type) {
+		case Indexable:
+			i, err := strconv.Atoi(key)
+			if err != nil {
+				return reflect.Value{}, newError(ErrGet, "invalid array index: %s", key)
+			}
+			if i < 0 || i >= d.Len() {
+				return reflect.Value{}, newError(ErrIndexOutOfRange, "index %d exceeds array length of %d", i, d.Len())
+			}
+			return reflect.ValueOf(d.Index(i)), nil
+
+		case Keyable:
+			elm, ok := d.Value(key)
+			if !ok {
+				return reflect.Value{}, newError(ErrGet, "map has no key '%s'", key)
+			}
+			return reflect.ValueOf(elm), nil
+		}
+	}
+
 	switch doc.Kind() {
 	// -------------------------------------------------------------------------
 	// Pointer, Interface
@@ -391,18 +1012,23 @@ func getValue(doc reflect.Value, key string) (reflect.Value, error) {
 		if doc.IsNil() {
 			return reflect.Value{}, newError(ErrGet, "document value is nil")
 		}
-		return getValue(doc.Elem(), key)
+		return r.getValue(doc.Elem(), key)
 
 	// -------------------------------------------------------------------------
 	// Array, Slice
 	// -------------------------------------------------------------------------
 	case reflect.Array, reflect.Slice:
+		if r.MatchSelectors {
+			if field, value, ok := parseSelector(key); ok {
+				return r.selectElement(doc, field, value)
+			}
+		}
 		i, err := strconv.Atoi(key)
 		if err != nil {
 			return reflect.Value{}, newError(ErrGet, "invalid array index: %s", key)
 		}
-		if i >= doc.Len() {
-			return reflect.Value{}, newError(ErrGet, "index %d exceeds array length of %d", i, doc.Len())
+		if i < 0 || i >= doc.Len() {
+			return reflect.Value{}, newError(ErrIndexOutOfRange, "index %d exceeds array length of %d", i, doc.Len())
 		}
 		return doc.Index(i), nil
 
@@ -410,7 +1036,11 @@ func getValue(doc reflect.Value, key string) (reflect.Value, error) {
 	// Map
 	// -------------------------------------------------------------------------
 	case reflect.Map:
-		elmVal := doc.MapIndex(reflect.ValueOf(key))
+		keyVal, err := mapKey(doc, key)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		elmVal := doc.MapIndex(keyVal)
 		if !elmVal.IsValid() {
 			return reflect.Value{}, newError(ErrGet, "map has no key '%s'", key)
 		}
@@ -420,41 +1050,278 @@ func getValue(doc reflect.Value, key string) (reflect.Value, error) {
 	// Struct
 	// -------------------------------------------------------------------------
 	case reflect.Struct:
-		// try to get value field name
-		f := doc.FieldByName(key)
-		if f.IsValid() {
-			return f, nil
-		}
-
-		// try to get value by json tag
-		st := doc.Type()
-		for i := 0; i < st.NumField(); i++ {
-			sf := st.Field(i)
-			if jsonTag := sf.Tag.Get("json"); jsonTag != "" && jsonTag != "-" {
-				var commaIdx int
-				if commaIdx = strings.Index(jsonTag, ","); commaIdx < 0 {
-					commaIdx = len(jsonTag)
-				}
-				fieldName := jsonTag[:commaIdx]
-				if fieldName != "" && fieldName == key {
-					f = doc.Field(i)
-					return f, nil
-				}
+		var (
+			sf reflect.StructField
+			ok bool
+		)
+		if r.StrictTags {
+			sf, ok = findTaggedField(doc.Type(), key, r.tagName())
+		} else {
+			sf, ok = findField(doc.Type(), key, r.tagName())
+		}
+		if !ok && r.NormalizeKeys {
+			var err error
+			if sf, ok, err = findNormalizedField(doc.Type(), key, r.tagName(), r.StrictTags); err != nil {
+				return reflect.Value{}, err
 			}
 		}
+		if !ok {
+			return reflect.Value{}, newError(ErrGet, "struct has no field '%s'", key)
+		}
+		f, err := doc.FieldByIndexErr(sf.Index)
+		if err != nil {
+			return reflect.Value{}, newError(ErrGet, "document value is nil")
+		}
+		return f, nil
 
-		return reflect.Value{}, newError(ErrGet, "struct has no field '%s'", key)
+	// -------------------------------------------------------------------------
+	// Func
+	// -------------------------------------------------------------------------
+	case reflect.Func:
+		if !r.CallFuncs {
+			break
+		}
+		if doc.IsNil() {
+			return reflect.Value{}, newError(ErrGet, "document value is nil")
+		}
+		var (
+			val interface{}
+			err error
+		)
+		switch f := doc.Interface().(type) {
+		case func() interface{}:
+			val = f()
+		case func() (interface{}, error):
+			val, err = f()
+		default:
+			return reflect.Value{}, newError(ErrGet, "unsupported function type %s", doc.Type())
+		}
+		if err != nil {
+			return reflect.Value{}, wrapError(err, ErrGet, "failed to compute document value: %s", err)
+		}
+		return r.getValue(reflect.ValueOf(val), key)
 
 	// -------------------------------------------------------------------------
 	// Primitive
 	// -------------------------------------------------------------------------
 	case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
-		return reflect.Value{}, newError(ErrGet, "primitive value has no fields")
+		if r.ScalarsAsArrays {
+			if i, err := strconv.Atoi(key); err == nil {
+				if i != 0 {
+					return reflect.Value{}, newError(ErrIndexOutOfRange, "index %d exceeds array length of 1", i)
+				}
+				return doc, nil
+			}
+		}
+		return reflect.Value{}, newError(ErrGet, "cannot resolve token '%s' in primitive value of type %s", key, doc.Type())
 	}
 
 	return reflect.Value{}, newError(ErrGet, "unsupported document type %s", doc.Kind())
 }
 
+// mapKey converts the token into a key for the given map. Tokens are parsed
+// into integer, float and boolean keys as needed; boolean keys are addressed by
+// the tokens "true" and "false".
+func mapKey(doc reflect.Value, key string) (reflect.Value, error) {
+	return convertKey(doc.Type().Key(), key, ErrGet)
+}
+
+// convertKey converts the token into a map key of the given type like mapKey.
+// Failures are reported as errors of the given type.
+func convertKey(keyType reflect.Type, key string, errType ErrType) (reflect.Value, error) {
+	switch keyType.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(key).Convert(keyType), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, newError(errType, "invalid key '%s' for map with key type %s", key, keyType)
+		}
+		return reflect.ValueOf(i).Convert(keyType), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(key, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, newError(errType, "invalid key '%s' for map with key type %s", key, keyType)
+		}
+		return reflect.ValueOf(u).Convert(keyType), nil
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(key, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, newError(errType, "invalid key '%s' for map with key type %s", key, keyType)
+		}
+		return reflect.ValueOf(f).Convert(keyType), nil
+
+	case reflect.Bool:
+		if key != "true" && key != "false" {
+			return reflect.Value{}, newError(errType, "invalid key '%s' for map with key type %s", key, keyType)
+		}
+		return reflect.ValueOf(key == "true").Convert(keyType), nil
+	}
+
+	keyVal := reflect.ValueOf(key)
+	if !keyVal.Type().AssignableTo(keyType) {
+		return reflect.Value{}, newError(errType, "unsupported map key type %s", keyType)
+	}
+	return keyVal, nil
+}
+
+// findField returns the struct field that is addressed by the key, either by
+// its Go name or by its name given by the struct tag with the given key.
+func findField(st reflect.Type, key, tag string) (reflect.StructField, bool) {
+	// try to get field by name, unless it is excluded by its tag
+	if sf, ok := st.FieldByName(key); ok && sf.Tag.Get(tag) != "-" {
+		return sf, true
+	}
+
+	// try to get field by json tag
+	if key == "" {
+		return reflect.StructField{}, false
+	}
+	return findTaggedField(st, key, tag)
+}
+
+// findTaggedField returns the struct field whose name, as given by the struct
+// tag with the given key or else its Go name, is the key. Like encoding/json,
+// it promotes the fields of embedded structs and pointers to structs without a
+// tag name. Shallower fields take precedence over deeper ones; of multiple
+// fields at the same depth, only a single tagged one is returned. The returned
+// field's Index is relative to st.
+func findTaggedField(st reflect.Type, key, tag string) (reflect.StructField, bool) {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	type match struct {
+		sf     reflect.StructField
+		tagged bool
+	}
+	current := []embedded{{st, nil}}
+	visited := map[reflect.Type]bool{}
+	for len(current) > 0 {
+		var (
+			next    []embedded
+			matches []match
+		)
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				sf.Index = append(append([]int{}, e.index...), i)
+				if sf.Tag.Get(tag) == "-" {
+					continue
+				}
+				name := tagName(sf, tag)
+				if sf.Anonymous && name == "" {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, embedded{ft, sf.Index})
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				if name == key {
+					matches = append(matches, match{sf, true})
+				} else if name == "" && sf.Name == key {
+					matches = append(matches, match{sf, false})
+				}
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			current = next
+			continue
+		case 1:
+			return matches[0].sf, true
+		}
+		var (
+			tagged reflect.StructField
+			count  int
+		)
+		for _, m := range matches {
+			if m.tagged {
+				tagged = m.sf
+				count++
+			}
+		}
+		return tagged, count == 1
+	}
+	return reflect.StructField{}, false
+}
+
+// findNormalizedField returns the struct field whose Go name or tag name
+// matches the key after normalizing both with normalizeKey. If strict is set,
+// only the tag name is matched, or the Go name for fields without one. It
+// fails if more than one field matches. Keys that normalize to an empty string
+// match no field. Unexported fields and fields tagged with "-" are skipped.
+func findNormalizedField(st reflect.Type, key, tag string, strict bool) (reflect.StructField, bool, error) {
+	normKey := normalizeKey(key)
+	if normKey == "" {
+		return reflect.StructField{}, false, nil
+	}
+	var (
+		match reflect.StructField
+		found bool
+	)
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() || sf.Tag.Get(tag) == "-" {
+			continue
+		}
+		name := tagName(sf, tag)
+		if strict {
+			if name == "" {
+				name = sf.Name
+			}
+			if normalizeKey(name) != normKey {
+				continue
+			}
+		} else if normalizeKey(sf.Name) != normKey && (name == "" || normalizeKey(name) != normKey) {
+			continue
+		}
+		if found {
+			return reflect.StructField{}, false, newError(ErrGet, "struct field '%s' is ambiguous", key)
+		}
+		match, found = sf, true
+	}
+	return match, found, nil
+}
+
+// normalizeKey case-folds the key and strips underscores and hyphens, so that
+// e.g. "DB_HOST", "db-host" and "dbHost" are normalized alike.
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+}
+
+// tagName returns the name of the struct field as given by the struct tag with
+// the given key or an empty string if the tag is missing, empty or "-".
+func tagName(sf reflect.StructField, tag string) string {
+	tagVal := sf.Tag.Get(tag)
+	if tagVal == "" || tagVal == "-" {
+		return ""
+	}
+	if commaIdx := strings.Index(tagVal, ","); commaIdx >= 0 {
+		return tagVal[:commaIdx]
+	}
+	return tagVal
+}
+
 // The ABNF syntax of a JSON Pointer is:
 // json-pointer    = *( "/" reference-token )
 // reference-token = *( unescaped / escaped )
@@ -462,7 +1329,9 @@ func getValue(doc reflect.Value, key string) (reflect.Value, error) {
 //    ; %x2F ('/') and %x7E ('~') are excluded from 'unescaped'
 // escaped         = "~" ( "0" / "1" )
 //   ; representing '~' and '/', respectively
-func parse(str string) (Pointer, error) {
+//
+// In strict mode, every '~' must be followed by either '0' or '1'.
+func (d *Dialect) parse(str string) (Pointer, error) {
 	if len(str) == 0 {
 		return Pointer{}, nil
 	}
@@ -473,10 +1342,29 @@ func parse(str string) (Pointer, error) {
 	str = str[1:]
 
 	toks := strings.Split(str, separator)
-	for i, t := range toks {
-		toks[i] = unescapeToken(t)
+	newPtr := make(Pointer, 0, len(toks))
+	for _, t := range toks {
+		if d.MaxTokenLength > 0 && len(t) > d.MaxTokenLength {
+			return nil, newError(ErrInvalidJSONPointer, "token exceeds maximum length of %d", d.MaxTokenLength)
+		}
+		if d.strict {
+			if err := validateToken(t); err != nil {
+				return nil, err
+			}
+		}
+		if d.CollapseEmptyTokens && t == "" {
+			continue
+		}
+		t, err := d.decodeToken(t)
+		if err != nil {
+			return nil, err
+		}
+		if d.ValidateUTF8 && !utf8.ValidString(t) {
+			return nil, newError(ErrInvalidJSONPointer, "token is not valid UTF-8")
+		}
+		newPtr = append(newPtr, unescapeToken(t))
 	}
-	return Pointer(toks), nil
+	return newPtr, nil
 }
 
 const (
@@ -486,6 +1374,21 @@ const (
 	escapedTilde     = "~0"
 )
 
+// validateToken checks that the escaped token only contains valid escape
+// sequences.
+func validateToken(tok string) error {
+	for i := 0; i < len(tok); i++ {
+		if tok[i] != '~' {
+			continue
+		}
+		if i+1 >= len(tok) || (tok[i+1] != '0' && tok[i+1] != '1') {
+			return newError(ErrInvalidJSONPointer, "invalid escape sequence at offset %d in token '%s'", i, tok)
+		}
+		i++
+	}
+	return nil
+}
+
 func unescapeToken(tok string) string {
 	tok = strings.Replace(tok, escapedSeparator, separator, -1)
 	return strings.Replace(tok, escapedTilde, tilde, -1)
@@ -2,8 +2,12 @@ package jsonpointer
 
 import (
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -122,8 +126,8 @@ func TestEval(t *testing.T) {
 		{"https://example.com#/m~0n", float64(8), ""},
 
 		// bad references
-		{"/foo/bar", nil, "get: invalid array index: bar"},
-		{"/foo/3", nil, "get: index 3 exceeds array length of 2"},
+		{"/foo/bar", nil, "get: at /foo: invalid array index: bar"},
+		{"/foo/3", nil, "get: at /foo: index 3 exceeds array length of 2"},
 		{"/bar/baz", nil, "get: map has no key 'bar'"},
 	}
 
@@ -220,6 +224,114 @@ func BenchmarkEval(b *testing.B) {
 	}
 }
 
+func TestEmptyToken(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+	doc := map[string]interface{}{
+		"":    "empty key",
+		"foo": map[string]*Item{"": {Name: "a"}},
+	}
+
+	root, _ := New("")
+	emptyKey, _ := New("/")
+	if !reflect.DeepEqual(root, Pointer{}) || !reflect.DeepEqual(emptyKey, Pointer{""}) {
+		t.Fatalf("expected root and empty key pointers, got: %#v, %#v", root, emptyKey)
+	}
+	if root.String() != "" || emptyKey.String() != "/" {
+		t.Errorf("expected strings '' and '/', got: '%s', '%s'", root, emptyKey)
+	}
+	if !root.IsEmpty() || emptyKey.IsEmpty() {
+		t.Errorf("expected only the root pointer to be empty")
+	}
+
+	if got, _ := root.Get(doc); !reflect.DeepEqual(got, doc) {
+		t.Errorf("'': value mismatch, expected: %#v, got: %#v", doc, got)
+	}
+	if got, _ := emptyKey.Get(doc); got != "empty key" {
+		t.Errorf("'/': value mismatch, expected: %#v, got: %#v", "empty key", got)
+	}
+
+	ptr := Pointer{"foo", "", "Name"}
+	if ptr.String() != "/foo//Name" {
+		t.Errorf("expected string '/foo//Name', got: '%s'", ptr)
+	}
+	if err := ptr.Set(doc, "b"); err != nil {
+		t.Errorf("%s: expected no error, got: %s", ptr, err)
+	}
+	if got, _ := ptr.Get(doc); got != "b" {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, "b", got)
+	}
+
+	// relative to the root and to the empty key
+	rel, err := Pointer{"", "x"}.RelativeTo(root)
+	if err != nil || !reflect.DeepEqual(rel, Pointer{"", "x"}) {
+		t.Errorf("expected %#v relative to the root, got: %#v (%v)", Pointer{"", "x"}, rel, err)
+	}
+	rel, err = Pointer{"", "x"}.RelativeTo(emptyKey)
+	if err != nil || !reflect.DeepEqual(rel, Pointer{"x"}) {
+		t.Errorf("expected %#v relative to the empty key, got: %#v (%v)", Pointer{"x"}, rel, err)
+	}
+}
+
+func TestErrorPosition(t *testing.T) {
+	errBoom := errors.New("boom")
+	doc := map[string]interface{}{
+		"foo": []interface{}{1, 2, map[string]interface{}{}},
+		"fn":  func() (interface{}, error) { return nil, errBoom },
+		"num": 1,
+	}
+
+	cases := []struct {
+		ptrstring string
+		set       bool
+		token     string
+		index     int
+		err       string
+	}{
+		{"/foo/2/bar", false, "bar", 2, "get: at /foo/2: map has no key 'bar'"},
+		{"/foo/3", false, "3", 1, "get: at /foo: index 3 exceeds array length of 3"},
+		{"/bar", false, "bar", 0, "get: map has no key 'bar'"},
+		{"/fn/x", false, "x", 1, "get: at /fn: failed to compute document value: boom"},
+		{"/foo/2/bar", true, "bar", 2, "get: at /foo/2: map has no key 'bar'"},
+		{"/num/x", true, "x", 1, "get: at /num: cannot resolve token 'x' in primitive value of type int"},
+	}
+
+	r := Resolver{CallFuncs: true}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		var err error
+		if c.set {
+			err = r.Set(ptr, doc, 1)
+		} else {
+			_, err = r.Get(ptr, doc)
+		}
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: expected *Error, got: %T", c.ptrstring, err)
+			continue
+		}
+		if perr.Token != c.token || perr.Index != c.index {
+			t.Errorf("%s: expected token '%s' at index %d, got: '%s' at index %d", c.ptrstring, c.token, c.index, perr.Token, perr.Index)
+		}
+	}
+
+	// the cause is still accessible
+	ptr, _ := New("/fn/x")
+	if _, err := r.Get(ptr, doc); !errors.Is(err, errBoom) {
+		t.Errorf("%s: expected error to wrap the cause, got: %v", ptr, err)
+	}
+
+	// errors not related to a token have no position
+	_, err := NewStrict("/foo~")
+	if perr, ok := err.(*Error); !ok || perr.Index != -1 {
+		t.Errorf("expected *Error with index -1, got: %#v", err)
+	}
+}
+
 func TestEscapeToken(t *testing.T) {
 	cases := []struct {
 		input  string
@@ -234,3 +346,1406 @@ func TestEscapeToken(t *testing.T) {
 		}
 	}
 }
+
+func TestPointerJSON(t *testing.T) {
+	type Config struct {
+		Target Pointer `json:"target"`
+	}
+
+	cases := []struct {
+		ptr  Pointer
+		json string
+	}{
+		{Pointer{"foo", "bar"}, `{"target":"/foo/bar"}`},
+		{Pointer{"a/b", "m~n", ""}, `{"target":"/a~1b/m~0n/"}`},
+		{Pointer{}, `{"target":""}`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(Config{Target: c.ptr})
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.ptr, err)
+			continue
+		}
+		if string(data) != c.json {
+			t.Errorf("%s: expected: %s, got: %s", c.ptr, c.json, data)
+		}
+		var got Config
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.ptr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Target, c.ptr) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptr, c.ptr, got.Target)
+		}
+	}
+
+	errCases := []struct {
+		json string
+		err  string
+	}{
+		{`{"target":"foo"}`, "invalid pointer: non-empty references must begin with a '/' character"},
+		{`{"target":["foo"]}`, "invalid pointer: pointer must be a JSON string: json: cannot unmarshal array into Go value of type string"},
+	}
+	for _, c := range errCases {
+		var got Config
+		err := json.Unmarshal([]byte(c.json), &got)
+		if errType(err) != ErrInvalidJSONPointer {
+			t.Errorf("%s: expected error of type %s, got: %s", c.json, ErrInvalidJSONPointer, errType(err))
+		}
+		assertError(t, c.json, err, c.err)
+	}
+}
+
+func TestPointerText(t *testing.T) {
+	type Config struct {
+		Target Pointer `xml:"target,attr"`
+		Source Pointer `xml:"source"`
+	}
+
+	cfg := Config{Target: Pointer{"a/b", "0"}, Source: Pointer{}}
+	data, err := xml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	expect := `<Config target="/a~1b/0"><source></source></Config>`
+	if string(data) != expect {
+		t.Errorf("expected: %s, got: %s", expect, data)
+	}
+	var got Config
+	if err := xml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("value mismatch, expected: %#v, got: %#v", cfg, got)
+	}
+
+	err = xml.Unmarshal([]byte(`<Config target="a~1b"></Config>`), &got)
+	assertError(t, "a~1b", err, "invalid pointer: non-empty references must begin with a '/' character")
+}
+
+func TestDisplayString(t *testing.T) {
+	cases := []struct {
+		ptrstring string
+		delim     string
+		expect    string
+	}{
+		{"/config/a~1b/0", " > ", "config > a/b > 0"},
+		{"/config/a~1b/0", ".", "config.a/b.0"},
+		{"/m~0n", ".", "m~n"},
+		{"", ".", ""},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		if got := ptr.DisplayString(c.delim); got != c.expect {
+			t.Errorf("%s: expected: '%s', got: '%s'", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestNewStrict(t *testing.T) {
+	cases := []struct {
+		raw    string
+		expect Pointer
+		err    string
+	}{
+		{"/a~0b/c~1d", Pointer{"a~b", "c/d"}, ""},
+		{"#/a~1b", Pointer{"a/b"}, ""},
+		{"/foo/bar~", nil, "invalid pointer: invalid escape sequence at offset 3 in token 'bar~'"},
+		{"/a~2b", nil, "invalid pointer: invalid escape sequence at offset 1 in token 'a~2b'"},
+		{"/foo/~~0", nil, "invalid pointer: invalid escape sequence at offset 0 in token '~~0'"},
+		{"/a~0~1~x", nil, "invalid pointer: invalid escape sequence at offset 5 in token 'a~0~1~x'"},
+	}
+
+	for _, c := range cases {
+		got, err := NewStrict(c.raw)
+		if assertError(t, c.raw, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.raw, c.expect, got)
+		}
+	}
+
+	// the lenient New accepts malformed escape sequences
+	if _, err := New("/a~2b"); err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	valid := []string{"", "#", "/foo", "/foo/0", "/a~1b", "/m~0n"}
+	if err := ValidateAll(valid); err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+
+	invalid := []string{"#7", "/a~2b", "/c~"}
+	err := ValidateAll(append(valid, invalid...))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected an aggregated error, got: %T", err)
+	}
+	errs := joined.Unwrap()
+	if len(errs) != len(invalid) {
+		t.Fatalf("expected %d errors, got %d: %s", len(invalid), len(errs), err)
+	}
+	for i, e := range errs {
+		var perr *Error
+		if !errors.As(e, &perr) || perr.errType != ErrInvalidJSONPointer {
+			t.Errorf("%s: expected an invalid pointer error, got: %s", invalid[i], e)
+		}
+		if !strings.Contains(e.Error(), invalid[i]) {
+			t.Errorf("%s: expected error to mention the input, got: %s", invalid[i], e)
+		}
+	}
+}
+
+func TestDivergeAt(t *testing.T) {
+	cases := []struct {
+		a      string
+		b      string
+		expect int
+	}{
+		{"/foo/bar/baz", "/foo/bar/qux", 2},
+		{"/foo/bar", "/qux/bar", 0},
+		{"/foo", "/foo/bar/baz", 1},
+		{"/foo/bar/baz", "/foo", 1},
+		{"", "/foo", 0},
+		{"/foo/bar", "/foo/bar", 2},
+		{"", "", 0},
+	}
+
+	for _, c := range cases {
+		a, _ := New(c.a)
+		b, _ := New(c.b)
+		if got := a.DivergeAt(b); got != c.expect {
+			t.Errorf("%s, %s: expected: %d, got: %d", c.a, c.b, c.expect, got)
+		}
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	cases := []struct {
+		ptr    string
+		prefix string
+		expect bool
+	}{
+		{"/a/b", "/a", true},
+		{"/a/b", "/a/b", true},
+		{"/a/b", "", true},
+		{"/a", "/a/b", false},
+		{"/ab", "/a", false},
+		{"/a/b", "/b", false},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptr)
+		prefix, _ := New(c.prefix)
+		if got := ptr.HasPrefix(prefix); got != c.expect {
+			t.Errorf("%s, %s: expected: %t, got: %t", c.ptr, c.prefix, c.expect, got)
+		}
+	}
+}
+
+func TestSetMapElements(t *testing.T) {
+	newDoc := func() map[string]interface{} {
+		return map[string]interface{}{
+			"name":   "foo",
+			"nested": map[string]interface{}{"a": 1},
+			"list":   []interface{}{"x"},
+			"typed":  map[string]int{"n": 1},
+		}
+	}
+
+	cases := []struct {
+		ptrstring string
+		value     interface{}
+		expect    interface{}
+		err       string
+	}{
+		{"/name", "bar", "bar", ""},
+		{"/name", 42, 42, ""},
+		{"/name", nil, nil, ""},
+		{"/nested", map[string]interface{}{"b": 2}, map[string]interface{}{"b": 2}, ""},
+		{"/nested/a", "x", "x", ""},
+		{"/list/0", "y", "y", ""},
+		{"/typed/n", 2, 2, ""},
+		{"/typed/n", "3", 3, ""},
+		{"/typed/n", "x", nil, "set: at /typed: conversion failed (string ➜ int)"},
+		{"/missing", "x", nil, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		doc := newDoc()
+		ptr, _ := New(c.ptrstring)
+		err := ptr.Set(doc, c.value)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if got, _ := ptr.Get(doc); !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestSetRecursive(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+	newDoc := func() map[string]interface{} {
+		return map[string]interface{}{
+			"a":     map[string]interface{}{"x": 1},
+			"null":  nil,
+			"str":   "foo",
+			"item":  &Item{},
+			"typed": map[string]map[string]int{"x": {"a": 1}},
+			"deep":  map[string]map[string]map[string]int{},
+			"iface": map[string]fmt.Stringer{},
+		}
+	}
+
+	cases := []struct {
+		ptrstring string
+		value     interface{}
+		check     string
+		expect    interface{}
+		err       string
+	}{
+		{"/a/b/c", 1, "/a", map[string]interface{}{"x": 1, "b": map[string]interface{}{"c": 1}}, ""},
+		{"/b/c/d", "v", "/b", map[string]interface{}{"c": map[string]interface{}{"d": "v"}}, ""},
+		{"/a/x", 2, "/a", map[string]interface{}{"x": 2}, ""},
+		{"/null/b", true, "/null", map[string]interface{}{"b": true}, ""},
+		{"/a/y", nil, "/a", map[string]interface{}{"x": 1, "y": nil}, ""},
+		{"/item/Name", "bar", "/item/Name", "bar", ""},
+		{"/str/b/c", 1, "", nil, "set: cannot create key 'b' in primitive value of type string"},
+		{"/item/Missing", 1, "", nil, "get: struct has no field 'Missing'"},
+		{"/typed/y/b", 2, "/typed", map[string]map[string]int{"x": {"a": 1}, "y": {"b": 2}}, ""},
+		{"/typed/x/b", 2, "/typed/x", map[string]int{"a": 1, "b": 2}, ""},
+		{"/deep/a/b/c", 1, "/deep", map[string]map[string]map[string]int{"a": {"b": {"c": 1}}}, ""},
+		{"/typed/y/b", "v", "", nil, "set: conversion failed (string ➜ int)"},
+		{"/iface/a/b", 1, "", nil, "set: cannot create value of type fmt.Stringer for key 'a'"},
+	}
+
+	for _, c := range cases {
+		doc := newDoc()
+		ptr, _ := New(c.ptrstring)
+		err := ptr.SetRecursive(doc, c.value)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		check, _ := New(c.check)
+		got, err := check.Get(doc)
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.ptrstring, err)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestGetMapOfSlices(t *testing.T) {
+	doc := map[string]interface{}{
+		"tags": map[string][]string{
+			"prod": {"web", "db"},
+		},
+		"labels": map[string]string{
+			"env": "prod",
+		},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/tags/prod", []string{"web", "db"}, ""},
+		{"/tags/prod/0", "web", ""},
+		{"/tags/prod/1", "db", ""},
+		{"/tags/prod/2", nil, "get: at /tags/prod: index 2 exceeds array length of 2"},
+		{"/labels/env/0", nil, "get: at /labels/env: cannot resolve token '0' in primitive value of type string"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.Get(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestGetMapOfPointers(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+	var nilItems *[]Item
+	items := []Item{{Name: "foo"}, {Name: "bar"}}
+	byName := map[string]Item{"baz": {Name: "baz"}}
+	doc := map[string]interface{}{
+		"cache": map[string]*[]Item{"0": &items, "nil": nilItems},
+		"index": map[string]*map[string]Item{"names": &byName},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/cache/0/0/name", "foo", ""},
+		{"/cache/0/1", Item{Name: "bar"}, ""},
+		{"/cache/0/2", nil, "get: at /cache/0: index 2 exceeds array length of 2"},
+		{"/cache/nil/0", nil, "get: at /cache/nil: document value is nil"},
+		{"/index/names/baz/name", "baz", ""},
+		{"/index/names/qux", nil, "get: at /index/names: map has no key 'qux'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.Get(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// values behind the pointers can be set
+	ptr, _ := New("/cache/0/1/name")
+	if err := ptr.Set(doc, "qux"); err != nil {
+		t.Fatalf("%s: expected no error, got: %s", ptr, err)
+	}
+	if items[1].Name != "qux" {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, "qux", items[1].Name)
+	}
+	ptr, _ = New("/index/names/baz/name")
+	if err := ptr.Set(doc, "qux"); err != nil {
+		t.Fatalf("%s: expected no error, got: %s", ptr, err)
+	}
+	if byName["baz"].Name != "qux" {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, "qux", byName["baz"].Name)
+	}
+}
+
+func TestGetNonStringMapKeys(t *testing.T) {
+	type Key string
+	doc := map[string]interface{}{
+		"ints":   map[int]string{42: "foo", -1: "bar"},
+		"int64s": map[int64]interface{}{7: map[string]interface{}{"a": 1}},
+		"uints":  map[uint8]string{255: "baz"},
+		"floats": map[float64]string{1.5: "qux"},
+		"named":  map[Key]int{"k": 1},
+		"any":    map[interface{}]int{"k": 2},
+		"bools":  map[bool]int{true: 3, false: 4},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/ints/42", "foo", ""},
+		{"/ints/-1", "bar", ""},
+		{"/ints/43", nil, "get: at /ints: map has no key '43'"},
+		{"/ints/foo", nil, "get: at /ints: invalid key 'foo' for map with key type int"},
+		{"/int64s/7/a", 1, ""},
+		{"/int64s/7.5", nil, "get: at /int64s: invalid key '7.5' for map with key type int64"},
+		{"/uints/255", "baz", ""},
+		{"/uints/256", nil, "get: at /uints: invalid key '256' for map with key type uint8"},
+		{"/floats/1.5", "qux", ""},
+		{"/named/k", 1, ""},
+		{"/any/k", 2, ""},
+		{"/floats/1.50", "qux", ""},
+		{"/floats/2.5", nil, "get: at /floats: map has no key '2.5'"},
+		{"/floats/x", nil, "get: at /floats: invalid key 'x' for map with key type float64"},
+		{"/bools/true", 3, ""},
+		{"/bools/false", 4, ""},
+		{"/bools/1", nil, "get: at /bools: invalid key '1' for map with key type bool"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.Get(doc)
+		if err != nil && errType(err) != ErrGet {
+			t.Errorf("%s: expected error of type %s, got: %s", c.ptrstring, ErrGet, errType(err))
+		}
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestFixedArrays(t *testing.T) {
+	matrix := [3][3]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}
+	doc := map[string]interface{}{"matrix": matrix}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/matrix/1/2", 6, ""},
+		{"/matrix/2", [3]int{7, 8, 9}, ""},
+		{"/matrix/3/0", nil, "get: at /matrix: index 3 exceeds array length of 3"},
+		{"/matrix/0/x", nil, "get: at /matrix/0: invalid array index: x"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.Get(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// arrays passed by value cannot be modified
+	ptr, _ := New("/1/2")
+	err := ptr.Set(matrix, 0)
+	assertError(t, ptr.String(), err, "set: cannot set value in array of type [3][3]int passed by value")
+
+	// arrays passed by pointer can be modified
+	if err := ptr.Set(&matrix, 0); err != nil {
+		t.Errorf("%s: expected no error, got: %s", ptr, err)
+	}
+	if matrix[1][2] != 0 {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, 0, matrix[1][2])
+	}
+}
+
+func TestParent(t *testing.T) {
+	cases := []struct {
+		ptrstring string
+		expect    Pointer
+	}{
+		{"/a/b", Pointer{"a"}},
+		{"/a/b/c", Pointer{"a", "b"}},
+		{"/a", Pointer{}},
+		{"", Pointer{}},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got := ptr.Parent()
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	ptr, _ := New("/a/b")
+	if parent := ptr.Parent(); parent.String() != "/a" || len(parent) != 1 {
+		t.Errorf("expected parent '/a' of length 1, got: '%s' of length %d", parent, len(parent))
+	}
+
+	// the parent does not share the tokens of the pointer
+	parent := ptr.Parent()
+	parent[0] = "x"
+	if ptr[0] != "a" {
+		t.Errorf("expected pointer to be unmodified, got: %s", ptr)
+	}
+}
+
+func TestLeaf(t *testing.T) {
+	cases := []struct {
+		ptrstring string
+		leaf      string
+		ok        bool
+	}{
+		{"/foo/bar", "bar", true},
+		{"/foo", "foo", true},
+		{"/foo/a~1b~0c", "a/b~c", true},
+		{"/", "", true},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		leaf, ok := ptr.Leaf()
+		if leaf != c.leaf || ok != c.ok {
+			t.Errorf("%s: expected: '%s', %t, got: '%s', %t", c.ptrstring, c.leaf, c.ok, leaf, ok)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		ptrstring string
+		n         int
+		expect    string
+	}{
+		{"/foo/bar/baz", 2, "/foo/bar"},
+		{"/foo/bar/baz", 1, "/foo"},
+		{"/foo/bar/baz", 0, ""},
+		{"/foo/bar/baz", -1, ""},
+		{"/foo/bar/baz", 3, "/foo/bar/baz"},
+		{"/foo/bar/baz", 10, "/foo/bar/baz"},
+		{"", 2, ""},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got := ptr.Truncate(c.n)
+		if got.String() != c.expect {
+			t.Errorf("%s, %d: expected: '%s', got: '%s'", c.ptrstring, c.n, c.expect, got)
+		}
+		if len(got) > 0 && &got[0] == &ptr[0] {
+			t.Errorf("%s, %d: expected a copy of the pointer", c.ptrstring, c.n)
+		}
+	}
+}
+
+func TestResolveDeepest(t *testing.T) {
+	doc := map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": []interface{}{"baz"},
+		},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		reached   string
+		err       string
+	}{
+		{"/foo/bar/0", "baz", "/foo/bar/0", ""},
+		{"/foo/qux/0/1", doc["foo"], "/foo", "get: map has no key 'qux'"},
+		{"/foo/bar/1/qux", []interface{}{"baz"}, "/foo/bar", "get: index 1 exceeds array length of 1"},
+		{"/qux", doc, "", "get: map has no key 'qux'"},
+		{"", doc, "", ""},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, reached, err := ptr.ResolveDeepest(doc)
+		assertError(t, c.ptrstring, err, c.err)
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+		if reached.String() != c.reached {
+			t.Errorf("%s: expected reached pointer: '%s', got: '%s'", c.ptrstring, c.reached, reached)
+		}
+	}
+
+	ptr, _ := New("/foo/qux/0/1")
+	if _, reached, _ := ptr.ResolveDeepest(doc); len(reached) != 1 {
+		t.Errorf("expected reached pointer of length 1, got: %d", len(reached))
+	}
+	ptr, _ = New("/foo/bar/1/qux")
+	if _, reached, _ := ptr.ResolveDeepest(doc); len(reached) != 2 {
+		t.Errorf("expected reached pointer of length 2, got: %d", len(reached))
+	}
+}
+
+func TestGetEmbeddedFields(t *testing.T) {
+	type Meta struct {
+		ID      string `json:"id"`
+		Version int    `json:"version"`
+	}
+	type Base struct {
+		Meta
+		ID int `json:"id"`
+	}
+	type Audit struct {
+		By string `json:"by"`
+	}
+	type Info struct {
+		Note string `json:"note"`
+	}
+	type A struct{ Code int }
+	type B struct{ Code int }
+	type Doc struct {
+		Base
+		*Audit
+		A
+		B
+		Info  `json:"info"`
+		Title string `json:"title"`
+	}
+	doc := Doc{
+		Base:  Base{Meta: Meta{ID: "meta", Version: 2}, ID: 1},
+		Audit: &Audit{By: "me"},
+		A:     A{Code: 1},
+		B:     B{Code: 2},
+		Info:  Info{Note: "n"},
+		Title: "t",
+	}
+
+	// resolve the same pointers against the JSON representation
+	var jsonDoc interface{}
+	data, _ := json.Marshal(doc)
+	json.Unmarshal(data, &jsonDoc)
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/id", 1, ""},
+		{"/version", 2, ""},
+		{"/by", "me", ""},
+		{"/info/note", "n", ""},
+		{"/title", "t", ""},
+		{"/note", nil, "get: struct has no field 'note'"},
+		{"/Code", nil, "get: struct has no field 'Code'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.Get(doc)
+		_, jsonErr := ptr.Get(jsonDoc)
+		if (err == nil) != (jsonErr == nil) {
+			t.Errorf("%s: expected resolution to match encoding/json, got: %v, %v", c.ptrstring, err, jsonErr)
+		}
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// promoted fields can be set
+	ptr, _ := New("/by")
+	if err := ptr.Set(&doc, "you"); err != nil {
+		t.Errorf("%s: expected no error, got: %s", ptr, err)
+	}
+	if doc.Audit.By != "you" {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, "you", doc.Audit.By)
+	}
+
+	// embedded nil pointers cannot be resolved
+	doc.Audit = nil
+	_, err := ptr.Get(doc)
+	assertError(t, ptr.String(), err, "get: document value is nil")
+}
+
+func TestFieldPointer(t *testing.T) {
+	type base struct {
+		ID int `json:"id,omitempty"`
+	}
+	type document struct {
+		base
+		Name    string `json:"name"`
+		Comment string
+		Ignored string `json:"-"`
+	}
+
+	cases := []struct {
+		structType interface{}
+		fieldName  string
+		expect     string
+		err        string
+	}{
+		{document{}, "Name", "/name", ""},
+		{&document{}, "Name", "/name", ""},
+		{reflect.TypeOf(document{}), "Name", "/name", ""},
+		{document{}, "Comment", "/Comment", ""},
+		{document{}, "Ignored", "", "invalid pointer: field 'Ignored' of struct jsonpointer.document is excluded by its tag"},
+		{document{}, "ID", "/id", ""},
+		{document{}, "Unknown", "", "invalid pointer: struct jsonpointer.document has no field 'Unknown'"},
+		{"foo", "Name", "", "invalid pointer: invalid struct type: string"},
+	}
+
+	for _, c := range cases {
+		got, err := FieldPointer(c.structType, c.fieldName)
+		if assertError(t, c.fieldName, err, c.err) {
+			continue
+		}
+		if got.String() != c.expect {
+			t.Errorf("%s: expected: '%s', got: '%s'", c.fieldName, c.expect, got)
+		}
+	}
+
+	// the pointer resolves the field
+	doc := document{Name: "foo"}
+	ptr, _ := FieldPointer(doc, "Name")
+	if got, err := ptr.Get(doc); err != nil || got != "foo" {
+		t.Errorf("expected value 'foo', got: %#v (%v)", got, err)
+	}
+}
+
+type testList []string
+
+func (l testList) Len() int                { return len(l) }
+func (l testList) Index(i int) interface{} { return "item " + l[i] }
+
+type testDict struct {
+	keys   []string
+	values []interface{}
+}
+
+func (d *testDict) Value(key string) (interface{}, bool) {
+	for i, k := range d.keys {
+		if k == key {
+			return d.values[i], true
+		}
+	}
+	return nil, false
+}
+
+func TestGetSkippedFields(t *testing.T) {
+	type Inner struct {
+		Secret string `json:"-"`
+	}
+	type Doc struct {
+		Name     string `json:"name"`
+		Password string `json:"-"`
+		Dash     string `json:"-,"`
+		Plain    string
+		Inner    Inner  `json:"inner"`
+		Addr     string `json:"address"`
+	}
+	doc := &Doc{Name: "n", Password: "p", Dash: "d", Plain: "x", Inner: Inner{Secret: "s"}, Addr: "a"}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/name", "n", ""},
+		{"/Name", "n", ""},
+		{"/Plain", "x", ""},
+		{"/-", "d", ""},
+		{"/Dash", "d", ""},
+		{"/Password", nil, "get: struct has no field 'Password'"},
+		{"/inner/Secret", nil, "get: at /inner: struct has no field 'Secret'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.Get(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// skipped fields are not matched by normalized keys either
+	for _, strict := range []bool{false, true} {
+		r := Resolver{NormalizeKeys: true, StrictTags: strict}
+		for _, c := range []struct {
+			ptrstring string
+			err       string
+		}{
+			{"/Password", "get: struct has no field 'Password'"},
+			{"/PASSWORD", "get: struct has no field 'PASSWORD'"},
+			{"/inner/SECRET", "get: at /inner: struct has no field 'SECRET'"},
+			{"/NAME", ""},
+		} {
+			ptr, _ := New(c.ptrstring)
+			_, err := r.Get(ptr, doc)
+			assertError(t, fmt.Sprintf("%s (strict: %t)", c.ptrstring, strict), err, c.err)
+		}
+	}
+
+	// with StrictTags, only the tag name of tagged fields is normalized
+	ptr, _ := New("/ADDR")
+	_, err := (&Resolver{NormalizeKeys: true}).Get(ptr, doc)
+	assertError(t, ptr.String(), err, "")
+	_, err = (&Resolver{NormalizeKeys: true, StrictTags: true}).Get(ptr, doc)
+	assertError(t, ptr.String(), err, "get: struct has no field 'ADDR'")
+
+	// skipped fields cannot be set either
+	ptr, _ = New("/Password")
+	err = ptr.Set(doc, "q")
+	assertError(t, ptr.String(), err, "get: struct has no field 'Password'")
+	if doc.Password != "p" {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, "p", doc.Password)
+	}
+}
+
+func TestGetCustomContainers(t *testing.T) {
+	doc := map[string]interface{}{
+		"dict": &testDict{
+			keys:   []string{"foo", "bar"},
+			values: []interface{}{1, testList{"a", "b"}},
+		},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/dict/foo", 1, ""},
+		{"/dict/bar/1", "item b", ""},
+		{"/dict/baz", nil, "get: at /dict: map has no key 'baz'"},
+		{"/dict/bar/2", nil, "get: at /dict/bar: index 2 exceeds array length of 2"},
+		{"/dict/bar/-1", nil, "get: at /dict/bar: index -1 exceeds array length of 2"},
+		{"/dict/bar/x", nil, "get: at /dict/bar: invalid array index: x"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.Get(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestGetOrSchemaDefault(t *testing.T) {
+	doc := map[string]interface{}{
+		"server": map[string]interface{}{"port": 8080},
+	}
+	schema := map[string]interface{}{
+		"server": map[string]interface{}{"host": "localhost", "port": 80},
+		"debug":  false,
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/server/port", 8080, ""},
+		{"/server/host", "localhost", ""},
+		{"/debug", false, ""},
+		{"/server", map[string]interface{}{"port": 8080}, ""},
+		{"/missing", nil, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.GetOrSchemaDefault(doc, schema)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestGetMutable(t *testing.T) {
+	type Server struct {
+		Ports []int `json:"ports"`
+	}
+	type Config struct {
+		Server  Server      `json:"server"`
+		Backend interface{} `json:"backend"`
+	}
+	doc := &Config{Server: Server{Ports: []int{80}}, Backend: "a"}
+
+	ptr, _ := New("/server/ports")
+	value, commit, err := ptr.GetMutable(doc)
+	if err != nil {
+		t.Fatalf("%s: expected no error, got: %s", ptr, err)
+	}
+	ports := append(value.([]int), 443)
+	ports[0] = 8080
+	if !reflect.DeepEqual(doc.Server.Ports, []int{80}) {
+		t.Errorf("%s: expected document to be unmodified, got: %#v", ptr, doc.Server.Ports)
+	}
+	if err := commit(ports); err != nil {
+		t.Fatalf("%s: expected no error, got: %s", ptr, err)
+	}
+	if !reflect.DeepEqual(doc.Server.Ports, []int{8080, 443}) {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, []int{8080, 443}, doc.Server.Ports)
+	}
+
+	// the document shape changed
+	ptr, _ = New("/backend")
+	_, commit, _ = ptr.GetMutable(doc)
+	doc.Backend = 1
+	err = commit("b")
+	assertError(t, ptr.String(), err, "set: document has changed: expected value of type string, got int")
+
+	ptr, _ = New("/missing")
+	_, _, err = ptr.GetMutable(doc)
+	assertError(t, ptr.String(), err, "get: struct has no field 'missing'")
+}
+
+func TestResolveField(t *testing.T) {
+	type Inner struct {
+		Count int `json:"count,string"`
+	}
+	type Outer struct {
+		Name  string `json:"name,omitempty"`
+		Inner *Inner `json:"inner"`
+		Plain float64
+	}
+	doc := map[string]interface{}{
+		"outer": &Outer{Name: "foo", Inner: &Inner{Count: 3}, Plain: 1.5},
+		"list":  []int{1},
+	}
+
+	cases := []struct {
+		ptrstring string
+		value     interface{}
+		ok        bool
+		fieldName string
+		tag       string
+		err       string
+	}{
+		{"/outer/name", "foo", true, "Name", "name,omitempty", ""},
+		{"/outer/inner/count", 3, true, "Count", "count,string", ""},
+		{"/outer/Plain", 1.5, true, "Plain", "", ""},
+		{"/outer", doc["outer"], false, "", "", ""},
+		{"/list/0", 1, false, "", "", ""},
+		{"", doc, false, "", "", ""},
+		{"/outer/missing", nil, false, "", "", "get: struct has no field 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		value, field, ok, err := ptr.ResolveField(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.value, value)
+		}
+		if ok != c.ok {
+			t.Errorf("%s: expected ok: %t, got: %t", c.ptrstring, c.ok, ok)
+		}
+		if field.Name != c.fieldName {
+			t.Errorf("%s: expected field: '%s', got: '%s'", c.ptrstring, c.fieldName, field.Name)
+		}
+		if tag := field.Tag.Get("json"); tag != c.tag {
+			t.Errorf("%s: expected json tag: '%s', got: '%s'", c.ptrstring, c.tag, tag)
+		}
+	}
+}
+
+func TestRangeDescent(t *testing.T) {
+	type step struct {
+		container interface{}
+		token     string
+		child     interface{}
+	}
+	bar := []interface{}{"baz", "qux"}
+	foo := map[string]interface{}{"bar": bar}
+	doc := map[string]interface{}{"foo": foo}
+
+	var steps []step
+	ptr, _ := New("/foo/bar/1")
+	err := ptr.RangeDescent(doc, func(container interface{}, token string, child interface{}) error {
+		steps = append(steps, step{container, token, child})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	expect := []step{
+		{doc, "foo", foo},
+		{foo, "bar", bar},
+		{bar, "1", "qux"},
+	}
+	if !reflect.DeepEqual(steps, expect) {
+		t.Errorf("descent mismatch, expected: %v, got: %v", expect, steps)
+	}
+
+	// stop on error returned by fn
+	stop := errors.New("stop")
+	steps = nil
+	err = ptr.RangeDescent(doc, func(container interface{}, token string, child interface{}) error {
+		steps = append(steps, step{container, token, child})
+		if token == "bar" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || len(steps) != 2 {
+		t.Errorf("expected to stop after 2 steps, got %d steps and error: %v", len(steps), err)
+	}
+
+	// stop on resolution error
+	steps = nil
+	ptr, _ = New("/foo/baz/1")
+	err = ptr.RangeDescent(doc, func(container interface{}, token string, child interface{}) error {
+		steps = append(steps, step{container, token, child})
+		return nil
+	})
+	assertError(t, ptr.String(), err, "get: map has no key 'baz'")
+	if len(steps) != 1 {
+		t.Errorf("expected 1 step, got: %d", len(steps))
+	}
+}
+
+func TestValidateForType(t *testing.T) {
+	type user struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+		age  int
+	}
+	type document struct {
+		User   *user                  `json:"user"`
+		Groups map[string][]user      `json:"groups"`
+		Matrix [2][2]int              `json:"matrix"`
+		Extra  map[string]interface{} `json:"extra"`
+		Ports  map[int]string         `json:"ports"`
+		Bytes  map[uint8][]int        `json:"bytes"`
+		Ratios map[float64]string     `json:"ratios"`
+		Flags  map[bool]string        `json:"flags"`
+	}
+	docType := reflect.TypeOf(&document{})
+
+	cases := []struct {
+		ptrstring string
+		err       string
+	}{
+		{"", ""},
+		{"/user/name", ""},
+		{"/User/Name", ""},
+		{"/user/tags/0", ""},
+		{"/groups/admins/0/name", ""},
+		{"/matrix/1/1", ""},
+		{"/extra/anything/goes", ""},
+		{"/user/email", "set: struct jsonpointer.user has no field 'email'"},
+		{"/user/age", "set: struct field 'age' of jsonpointer.user is unexported"},
+		{"/user/tags/foo", "set: invalid array index: foo"},
+		{"/matrix/2", "set: index 2 exceeds array length of 2"},
+		{"/user/name/0", "set: cannot resolve token '0' in primitive type string"},
+		{"/ports/8080", ""},
+		{"/ports/-1", ""},
+		{"/ports/http", "set: invalid key 'http' for map with key type int"},
+		{"/bytes/255/0", ""},
+		{"/bytes/256/0", "set: invalid key '256' for map with key type uint8"},
+		{"/ratios/0.5", ""},
+		{"/ratios/half", "set: invalid key 'half' for map with key type float64"},
+		{"/flags/true", ""},
+		{"/flags/false", ""},
+		{"/flags/1", "set: invalid key '1' for map with key type bool"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		assertError(t, c.ptrstring, ptr.ValidateForType(docType), c.err)
+	}
+}
+
+func TestMixedPath(t *testing.T) {
+	cases := []struct {
+		path   []interface{}
+		expect string
+		err    string
+	}{
+		{[]interface{}{"foo", 0, "bar"}, "/foo/0/bar", ""},
+		{[]interface{}{"a/b", uint8(12), int64(3)}, "/a~1b/12/3", ""},
+		{[]interface{}{}, "", ""},
+		{[]interface{}{"foo", 1.5}, "", "invalid pointer: invalid path element: float64"},
+	}
+
+	for _, c := range cases {
+		got, err := FromMixedPath(c.path)
+		if assertError(t, fmt.Sprint(c.path), err, c.err) {
+			continue
+		}
+		if got.String() != c.expect {
+			t.Errorf("%v: expected: '%s', got: '%s'", c.path, c.expect, got)
+		}
+	}
+
+	// round trip
+	path := []interface{}{"foo", 0, "bar", 12, "01", "-1", ""}
+	ptr, err := FromMixedPath(path)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if got := ptr.MixedPath(); !reflect.DeepEqual(got, path) {
+		t.Errorf("expected: %#v, got: %#v", path, got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	type Item struct {
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags"`
+		count int
+	}
+	type Doc struct {
+		Items []Item           `json:"items"`
+		Meta  map[string]int   `json:"meta"`
+		Any   interface{}      `json:"any"`
+		Ptr   *Item            `json:"ptr"`
+		ByKey map[string]*Item `json:"byKey"`
+	}
+	doc := &Doc{
+		Items: []Item{{Name: "a", Tags: []string{"x"}}},
+		Meta:  map[string]int{"n": 1},
+		Any:   Item{Name: "b"},
+		Ptr:   &Item{Name: "c"},
+		ByKey: map[string]*Item{"d": {Name: "d"}},
+	}
+
+	cases := []struct {
+		ptrstring string
+		settable  bool
+		value     interface{}
+		err       string
+	}{
+		{"/items/0/name", true, "A", ""},
+		{"/items/0/tags/0", true, "X", ""},
+		{"/items/0", true, Item{Name: "a2"}, ""},
+		{"/ptr/name", true, "C", ""},
+		{"/byKey/d/name", true, "D", ""},
+		{"/meta/n", false, nil, ""},
+		{"/any/name", false, nil, ""},
+		{"/items/0/count", false, nil, ""},
+		{"/items/1", false, nil, "get: at /items: index 1 exceeds array length of 1"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		val, err := ptr.Resolve(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if val.CanSet() != c.settable {
+			t.Errorf("%s: expected settable: %t, got: %t", c.ptrstring, c.settable, val.CanSet())
+			continue
+		}
+		if !c.settable {
+			continue
+		}
+		val.Set(reflect.ValueOf(c.value))
+		if got, _ := ptr.Get(doc); !reflect.DeepEqual(got, c.value) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.value, got)
+		}
+	}
+
+	// the value can be inspected and modified without resolving it again
+	ptr, _ := New("/items/0/tags")
+	val, err := ptr.Resolve(doc)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %s", ptr, err)
+	}
+	val.Set(reflect.Append(val, reflect.ValueOf("y")))
+	if expect := []string{"y"}; !reflect.DeepEqual(doc.Items[0].Tags, expect) {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, expect, doc.Items[0].Tags)
+	}
+}
+
+func TestGetWithPath(t *testing.T) {
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(docBytes, &doc); err != nil {
+		t.Fatalf("error unmarshaling document json: %s", err.Error())
+	}
+
+	cases := []struct {
+		ptrstring string
+		path      string
+		err       string
+	}{
+		{"/foo/1", "/foo/1", ""},
+		{"#/a~1b", "/a~1b", ""},
+		{"#/c%25d", "/c%d", ""},
+		{"", "", ""},
+		{"/bar", "/bar", "get: map has no key 'bar'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		value, path, err := ptr.GetWithPath(doc)
+		if path != c.path {
+			t.Errorf("%s: expected path: '%s', got: '%s'", c.ptrstring, c.path, path)
+		}
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		expect, _ := ptr.Get(doc)
+		if !reflect.DeepEqual(value, expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, expect, value)
+		}
+	}
+}
+
+func TestGetMap(t *testing.T) {
+	doc := map[string]interface{}{
+		"name": "foo",
+		"tags": []interface{}{"a", "b"},
+		"a/b":  1,
+	}
+
+	ptrs := []Pointer{{"name"}, {"tags", "1"}, {"missing"}, {"a/b"}, {"tags", "5"}}
+	values, errs := GetMap(doc, ptrs)
+
+	expect := map[string]interface{}{
+		"/name":   "foo",
+		"/tags/1": "b",
+		"/a~1b":   1,
+	}
+	if !reflect.DeepEqual(values, expect) {
+		t.Errorf("value mismatch, expected: %#v, got: %#v", expect, values)
+	}
+
+	expectErrs := []string{"", "", "get: map has no key 'missing'", "", "get: at /tags: index 5 exceeds array length of 2"}
+	if len(errs) != len(ptrs) {
+		t.Fatalf("expected %d errors, got: %d", len(ptrs), len(errs))
+	}
+	for i, err := range errs {
+		assertError(t, ptrs[i].String(), err, expectErrs[i])
+	}
+}
+
+func TestProbe(t *testing.T) {
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(docBytes, &doc); err != nil {
+		t.Fatalf("error unmarshaling document json: %s", err.Error())
+	}
+
+	cases := []struct {
+		ptrstring string
+		err       string
+	}{
+		{"", ""},
+		{"/foo/1", ""},
+		{"/m~0n", ""},
+		{"/foo/2", "get: at /foo: index 2 exceeds array length of 2"},
+		{"/bar", "get: map has no key 'bar'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		assertError(t, c.ptrstring, ptr.Probe(doc), c.err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	type Node struct {
+		Next *Node `json:"next"`
+		Name string
+	}
+	var nilMap map[string]interface{}
+	doc := map[string]interface{}{
+		"list": []interface{}{1, nil},
+		"node": &Node{Next: &Node{}},
+		"nil":  nilMap,
+	}
+
+	cases := []struct {
+		ptrstring string
+		exists    bool
+	}{
+		{"", true},
+		{"/list/1", true},
+		{"/list/2", false},
+		{"/list/1/foo", false},
+		{"/missing", false},
+		{"/node/next/Name", true},
+		{"/node/next/next", true},
+		{"/node/next/next/Name", false},
+		{"/nil/foo", false},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		if got := ptr.Exists(doc); got != c.exists {
+			t.Errorf("%s: expected exists: %t, got: %t", c.ptrstring, c.exists, got)
+		}
+	}
+	if ptr, _ := New("/foo"); ptr.Exists(nil) {
+		t.Errorf("/foo: expected pointer to not exist in nil document")
+	}
+
+	// resolver options apply
+	ptr, _ := New("/node/NEXT")
+	if ptr.Exists(doc) {
+		t.Errorf("%s: expected pointer to not exist without NormalizeKeys", ptr)
+	}
+	r := Resolver{NormalizeKeys: true, NotFoundValue: "n/a"}
+	if !r.Exists(ptr, doc) {
+		t.Errorf("%s: expected pointer to exist with NormalizeKeys", ptr)
+	}
+	ptr, _ = New("/missing")
+	if r.Exists(ptr, doc) {
+		t.Errorf("%s: expected pointer to not exist despite NotFoundValue", ptr)
+	}
+	assertError(t, ptr.String(), r.Probe(ptr, doc), "get: map has no key 'missing'")
+}
+
+func TestSplitAtResolvable(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": 1},
+		},
+	}
+
+	cases := []struct {
+		ptrstring  string
+		resolvable string
+		value      interface{}
+		remainder  string
+		err        string
+	}{
+		{"/a/b/x/y", "/a/b", doc["a"].(map[string]interface{})["b"], "/x/y", "get: map has no key 'x'"},
+		{"/a/b/c", "/a/b/c", 1, "", ""},
+		{"/x/y", "", doc, "/x/y", "get: map has no key 'x'"},
+		{"/a/b/c/d", "/a/b/c", 1, "/d", "get: cannot resolve token 'd' in primitive value of type int"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		resolvable, value, remainder, err := ptr.SplitAtResolvable(doc)
+		assertError(t, c.ptrstring, err, c.err)
+		if resolvable.String() != c.resolvable {
+			t.Errorf("%s: expected resolvable: '%s', got: '%s'", c.ptrstring, c.resolvable, resolvable)
+		}
+		if remainder.String() != c.remainder {
+			t.Errorf("%s: expected remainder: '%s', got: '%s'", c.ptrstring, c.remainder, remainder)
+		}
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.value, value)
+		}
+	}
+}
+
+func TestGetWithParent(t *testing.T) {
+	items := []interface{}{"a", "b"}
+	config := map[string]interface{}{"name": "foo", "items": items}
+	doc := map[string]interface{}{"config": config}
+
+	cases := []struct {
+		ptrstring string
+		value     interface{}
+		parent    interface{}
+		token     string
+		err       string
+	}{
+		{"/config/name", "foo", config, "name", ""},
+		{"/config/items/1", "b", items, "1", ""},
+		{"/config", config, doc, "config", ""},
+		{"", doc, nil, "", ""},
+		{"/config/missing", nil, nil, "", "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		value, parent, token, err := ptr.GetWithParent(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.value, value)
+		}
+		if !reflect.DeepEqual(parent, c.parent) {
+			t.Errorf("%s: parent mismatch, expected: %#v, got: %#v", c.ptrstring, c.parent, parent)
+		}
+		if token != c.token {
+			t.Errorf("%s: expected token: '%s', got: '%s'", c.ptrstring, c.token, token)
+		}
+	}
+
+	// the parent can be used to edit the value in place
+	ptr, _ := New("/config/items/0")
+	_, parent, token, _ := ptr.GetWithParent(doc)
+	i, _ := strconv.Atoi(token)
+	parent.([]interface{})[i] = "z"
+	if items[0] != "z" {
+		t.Errorf("expected the parent to share the document's array")
+	}
+}
@@ -0,0 +1,155 @@
+// Package protoptr resolves JSON pointers in protobuf messages. Messages,
+// repeated fields and map fields are adapted to the custom container
+// interfaces jsonpointer.Keyable and jsonpointer.Indexable, so that the
+// protobuf dependency is kept out of the jsonpointer package.
+package protoptr
+
+import (
+	"strconv"
+
+	"github.com/aisbergg/go-jsonpointer/pkg/jsonpointer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Get returns the value from the given protobuf message that the pointer
+// points to. Message fields are addressed by their proto field name or their
+// JSON name, repeated fields by their index and map fields by their key.
+//
+// Messages are returned as proto.Message, repeated and map fields as
+// protoreflect.List and protoreflect.Map and scalar fields as their Go value.
+func Get(m proto.Message, p jsonpointer.Pointer) (interface{}, error) {
+	var doc interface{}
+	if m != nil {
+		doc = Wrap(m)
+	}
+	value, err := p.Get(doc)
+	if err != nil {
+		return nil, err
+	}
+	return unwrap(value), nil
+}
+
+// Wrap adapts the message to jsonpointer.Keyable, so that it can be used as a
+// document with any function of the jsonpointer package. Resolved messages,
+// repeated fields and map fields are adapted as well.
+func Wrap(m proto.Message) jsonpointer.Keyable {
+	return message{m.ProtoReflect()}
+}
+
+// message adapts a protobuf message to jsonpointer.Keyable.
+type message struct {
+	msg protoreflect.Message
+}
+
+// Value returns the value of the field with the given proto field name or
+// JSON name.
+func (m message) Value(key string) (interface{}, bool) {
+	fields := m.msg.Descriptor().Fields()
+	fd := fields.ByName(protoreflect.Name(key))
+	if fd == nil {
+		fd = fields.ByJSONName(key)
+	}
+	if fd == nil {
+		return nil, false
+	}
+	return wrapValue(m.msg.Get(fd), fd), true
+}
+
+// list adapts a repeated field to jsonpointer.Indexable.
+type list struct {
+	list protoreflect.List
+	fd   protoreflect.FieldDescriptor
+}
+
+// Len returns the number of elements.
+func (l list) Len() int {
+	return l.list.Len()
+}
+
+// Index returns the element at the given index.
+func (l list) Index(i int) interface{} {
+	return wrapValue(l.list.Get(i), l.fd)
+}
+
+// mapField adapts a map field to jsonpointer.Keyable.
+type mapField struct {
+	m  protoreflect.Map
+	fd protoreflect.FieldDescriptor
+}
+
+// Value returns the value for the given key. Keys that cannot be converted to
+// the key type of the map do not exist.
+func (m mapField) Value(key string) (interface{}, bool) {
+	mapKey, ok := protoMapKey(m.fd.MapKey().Kind(), key)
+	if !ok {
+		return nil, false
+	}
+	elmVal := m.m.Get(mapKey)
+	if !elmVal.IsValid() {
+		return nil, false
+	}
+	return wrapValue(elmVal, m.fd.MapValue()), true
+}
+
+// wrapValue adapts messages, repeated fields and map fields to the custom
+// container interfaces and returns scalar values as their Go value. The field
+// descriptor describes the value.
+func wrapValue(val protoreflect.Value, fd protoreflect.FieldDescriptor) interface{} {
+	switch v := val.Interface().(type) {
+	case protoreflect.Message:
+		return message{v}
+	case protoreflect.List:
+		return list{v, fd}
+	case protoreflect.Map:
+		return mapField{v, fd}
+	}
+	return val.Interface()
+}
+
+// unwrap returns the protobuf value of an adapted value.
+func unwrap(value interface{}) interface{} {
+	switch v := value.(type) {
+	case message:
+		return v.msg.Interface()
+	case list:
+		return v.list
+	case mapField:
+		return v.m
+	}
+	return value
+}
+
+// protoMapKey converts the token into a map key of the given kind.
+func protoMapKey(kind protoreflect.Kind, key string) (protoreflect.MapKey, bool) {
+	switch kind {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(key).MapKey(), true
+
+	case protoreflect.BoolKind:
+		if b, err := strconv.ParseBool(key); err == nil {
+			return protoreflect.ValueOfBool(b).MapKey(), true
+		}
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if i, err := strconv.ParseInt(key, 10, 32); err == nil {
+			return protoreflect.ValueOfInt32(int32(i)).MapKey(), true
+		}
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if i, err := strconv.ParseInt(key, 10, 64); err == nil {
+			return protoreflect.ValueOfInt64(i).MapKey(), true
+		}
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if i, err := strconv.ParseUint(key, 10, 32); err == nil {
+			return protoreflect.ValueOfUint32(uint32(i)).MapKey(), true
+		}
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if i, err := strconv.ParseUint(key, 10, 64); err == nil {
+			return protoreflect.ValueOfUint64(i).MapKey(), true
+		}
+	}
+	return protoreflect.MapKey{}, false
+}
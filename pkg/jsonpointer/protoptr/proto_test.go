@@ -0,0 +1,100 @@
+package protoptr
+
+import (
+	"testing"
+
+	"github.com/aisbergg/go-jsonpointer/pkg/jsonpointer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestGet(t *testing.T) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name: proto.String("foo.proto"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Foo")},
+			{
+				Name: proto.String("Bar"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("baz"), Number: proto.Int32(1)},
+				},
+			},
+		},
+		Options: &descriptorpb.FileOptions{
+			JavaPackage: proto.String("com.example.foo"),
+		},
+	}
+	st, _ := structpb.NewStruct(map[string]interface{}{
+		"foo": map[string]interface{}{
+			"bar": []interface{}{"baz", 42},
+		},
+	})
+
+	cases := []struct {
+		msg       proto.Message
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		// nested
+		{file, "/name", "foo.proto", ""},
+		{file, "/options/java_package", "com.example.foo", ""},
+		{file, "/options/javaPackage", "com.example.foo", ""},
+
+		// repeated
+		{file, "/message_type/1/name", "Bar", ""},
+		{file, "/messageType/1/field/0/number", int32(1), ""},
+		{file, "/message_type/2", nil, "get: at /message_type: index 2 exceeds array length of 2"},
+		{file, "/message_type/foo", nil, "get: at /message_type: invalid array index: foo"},
+
+		// map
+		{st, "/fields/foo/struct_value/fields/bar/list_value/values/0/string_value", "baz", ""},
+		{st, "/fields/foo/structValue/fields/bar/listValue/values/1/numberValue", float64(42), ""},
+		{st, "/fields/qux", nil, "get: at /fields: map has no key 'qux'"},
+
+		// unknown
+		{file, "/foo", nil, "get: map has no key 'foo'"},
+		{nil, "/name", nil, "get: document value is invalid"},
+		{file, "/name/foo", nil, "get: at /name: cannot resolve token 'foo' in primitive value of type string"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := jsonpointer.New(c.ptrstring)
+		got, err := Get(c.msg, ptr)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// messages are returned as proto.Message
+	ptr, _ := jsonpointer.New("/options")
+	got, err := Get(file, ptr)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if !proto.Equal(got.(proto.Message), file.Options) {
+		t.Errorf("expected options message, got: %v", got)
+	}
+}
+
+func assertError(t *testing.T, key string, err error, expected string) (_break bool) {
+	if err != nil {
+		if expected != "" {
+			if expected != err.Error() {
+				t.Errorf("%s: expected error message: `%s`, got: `%s`", key, expected, err.Error())
+			}
+		} else {
+			t.Errorf("%s: expected no error, got: %s", key, err.Error())
+		}
+		return true
+
+	} else if expected != "" {
+		t.Errorf("%s: expected error with message: %s", key, expected)
+	}
+
+	return false
+}
@@ -0,0 +1,169 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// RelativePointerBetween returns the relative JSON pointer string (as defined
+// in draft-bhutton-relative-json-pointer) that addresses target when evaluated
+// at base, e.g. "2/foo/bar". The result ascends from base to the closest
+// common ancestor and descends from there into target.
+//
+// As relative pointers can ascend up to the document root, every absolute
+// target can be reached. An error is only returned if base or target is nil.
+func RelativePointerBetween(base, target Pointer) (string, error) {
+	if base == nil || target == nil {
+		return "", newError(ErrInvalidJSONPointer, "pointer must not be nil")
+	}
+	c := base.DivergeAt(target)
+	return strconv.Itoa(len(base)-c) + target[c:].String(), nil
+}
+
+// InvalidatedBy returns the subscribed pointers that are affected by a change
+// of the value at the changed pointer. These are the pointers equal to it, its
+// ancestors, whose values contain the changed value, and its descendants,
+// whose values may have been replaced or removed. The order of the subscribed
+// pointers is retained.
+func InvalidatedBy(changed Pointer, subscribed []Pointer) []Pointer {
+	var invalidated []Pointer
+	for _, s := range subscribed {
+		if s.HasPrefix(changed) || changed.HasPrefix(s) {
+			invalidated = append(invalidated, s)
+		}
+	}
+	return invalidated
+}
+
+// RelativePointer represents a parsed relative JSON pointer as defined in
+// draft-bhutton-relative-json-pointer, e.g. "1/foo", "0-1" or "2#". It is
+// evaluated at a base location of a document.
+type RelativePointer struct {
+	// Up is the number of levels to ascend from the base location.
+	Up int
+
+	// Offset is added to the array index of the location after ascending,
+	// e.g. -1 for "0-1". It is zero, if the pointer has no index
+	// manipulation.
+	Offset int
+
+	// Key reports whether the pointer ends with "#", i.e. whether it
+	// evaluates to the key or index of the location instead of its value.
+	Key bool
+
+	// Pointer is the pointer that is applied after ascending. It is empty, if
+	// Key is set.
+	Pointer Pointer
+}
+
+// NewRelative parses a relative JSON pointer. It consists of a non-negative
+// integer, an optional index manipulation like "+1" or "-1" and either a JSON
+// pointer or "#".
+func NewRelative(str string) (RelativePointer, error) {
+	var rp RelativePointer
+	n := leadingDigits(str)
+	if n == 0 {
+		return rp, newError(ErrInvalidJSONPointer, "relative pointer must start with a non-negative integer")
+	}
+	if n > 1 && str[0] == '0' {
+		return rp, newError(ErrInvalidJSONPointer, "relative pointer must not have leading zeros")
+	}
+	up, err := strconv.Atoi(str[:n])
+	if err != nil {
+		return rp, wrapError(err, ErrInvalidJSONPointer, "invalid level '%s'", str[:n])
+	}
+	rp.Up, str = up, str[n:]
+
+	if str != "" && (str[0] == '+' || str[0] == '-') {
+		n = leadingDigits(str[1:])
+		if n == 0 || n > 1 && str[1] == '0' {
+			return rp, newError(ErrInvalidJSONPointer, "invalid index manipulation in relative pointer")
+		}
+		offset, err := strconv.Atoi(str[:n+1])
+		if err != nil {
+			return rp, wrapError(err, ErrInvalidJSONPointer, "invalid index manipulation '%s'", str[:n+1])
+		}
+		rp.Offset, str = offset, str[n+1:]
+	}
+
+	if str == "#" {
+		rp.Key = true
+		return rp, nil
+	}
+	if rp.Pointer, err = defaultDialect.parse(str); err != nil {
+		return rp, err
+	}
+	return rp, nil
+}
+
+// leadingDigits returns the number of leading ASCII digits of the string.
+func leadingDigits(str string) int {
+	n := 0
+	for n < len(str) && str[n] >= '0' && str[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// String returns the string representation of the relative pointer.
+func (rp RelativePointer) String() string {
+	str := strconv.Itoa(rp.Up)
+	if rp.Offset > 0 {
+		str += "+"
+	}
+	if rp.Offset != 0 {
+		str += strconv.Itoa(rp.Offset)
+	}
+	if rp.Key {
+		return str + "#"
+	}
+	return str + rp.Pointer.String()
+}
+
+// Eval evaluates the relative pointer in the document at the base location.
+// It ascends from base by Up levels, adds Offset to the array index of the
+// resulting location and then either resolves Pointer from there or, if Key is
+// set, returns the key (a string) or array index (an int) of the location.
+func (rp RelativePointer) Eval(doc interface{}, base Pointer) (interface{}, error) {
+	if rp.Up > len(base) {
+		return nil, newError(ErrGet, "cannot ascend %d levels from %s", rp.Up, base)
+	}
+	loc := base.Truncate(len(base) - rp.Up)
+
+	var (
+		parent interface{}
+		err    error
+	)
+	if len(loc) > 0 && (rp.Offset != 0 || rp.Key) {
+		if parent, err = loc.Parent().Get(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if rp.Offset != 0 {
+		if len(loc) == 0 || jsonType(reflect.ValueOf(parent)) != "array" {
+			return nil, newError(ErrGet, "cannot manipulate index of non-array element %s", loc)
+		}
+		i, err := strconv.Atoi(loc[len(loc)-1])
+		if err != nil {
+			return nil, newError(ErrGet, "invalid array index: %s", loc[len(loc)-1])
+		}
+		loc = append(loc.Parent(), strconv.Itoa(i+rp.Offset))
+	}
+
+	if rp.Key {
+		if len(loc) == 0 {
+			return nil, newError(ErrGet, "document root has no key")
+		}
+		if _, err := loc.Get(doc); err != nil {
+			return nil, err
+		}
+		leaf := loc[len(loc)-1]
+		if jsonType(reflect.ValueOf(parent)) == "array" {
+			return strconv.Atoi(leaf)
+		}
+		return leaf, nil
+	}
+
+	return append(loc, rp.Pointer...).Get(doc)
+}
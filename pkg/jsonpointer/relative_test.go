@@ -0,0 +1,136 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelativePointerBetween(t *testing.T) {
+	cases := []struct {
+		base   string
+		target string
+		expect string
+	}{
+		// same
+		{"/foo/1", "/foo/1", "0"},
+		{"", "", "0"},
+		// siblings
+		{"/foo/1", "/foo/0", "1/0"},
+		{"/foo/bar", "/foo/a~1b", "1/a~1b"},
+		// descendants
+		{"/foo", "/foo/bar/baz", "0/bar/baz"},
+		{"", "/foo", "0/foo"},
+		// ancestors
+		{"/foo/bar/baz", "/foo", "2"},
+		{"/foo/bar", "", "2"},
+		// cousins
+		{"/a/b/c", "/a/x/y", "2/x/y"},
+		{"/a/b", "/c/d", "2/c/d"},
+	}
+
+	for _, c := range cases {
+		base, _ := New(c.base)
+		target, _ := New(c.target)
+		got, err := RelativePointerBetween(base, target)
+		if err != nil {
+			t.Errorf("%s, %s: expected no error, got: %s", c.base, c.target, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%s, %s: expected: '%s', got: '%s'", c.base, c.target, c.expect, got)
+		}
+	}
+
+	_, err := RelativePointerBetween(nil, Pointer{})
+	assertError(t, "nil", err, "invalid pointer: pointer must not be nil")
+}
+
+func TestInvalidatedBy(t *testing.T) {
+	var subscribed []Pointer
+	for _, s := range []string{"", "/a", "/a/b", "/a/b/c", "/a/bc", "/a/x", "/b"} {
+		ptr, _ := New(s)
+		subscribed = append(subscribed, ptr)
+	}
+
+	cases := []struct {
+		changed string
+		expect  []string
+	}{
+		{"/a/b", []string{"", "/a", "/a/b", "/a/b/c"}},
+		{"/a/b/c/d", []string{"", "/a", "/a/b", "/a/b/c"}},
+		{"/b", []string{"", "/b"}},
+		{"/c", []string{""}},
+		{"", []string{"", "/a", "/a/b", "/a/b/c", "/a/bc", "/a/x", "/b"}},
+	}
+
+	for _, c := range cases {
+		changed, _ := New(c.changed)
+		var got []string
+		for _, p := range InvalidatedBy(changed, subscribed) {
+			got = append(got, p.String())
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %v, got: %v", c.changed, c.expect, got)
+		}
+	}
+}
+
+func TestRelativePointer(t *testing.T) {
+	// examples from draft-bhutton-relative-json-pointer
+	doc := map[string]interface{}{
+		"foo": []interface{}{"bar", "baz"},
+		"highly": map[string]interface{}{
+			"nested": map[string]interface{}{
+				"objects": true,
+			},
+		},
+	}
+
+	cases := []struct {
+		base   string
+		rel    string
+		expect interface{}
+		err    string
+	}{
+		{"/foo/1", "0", "baz", ""},
+		{"/foo/1", "1/0", "bar", ""},
+		{"/foo/1", "0-1", "bar", ""},
+		{"/foo/1", "2/highly/nested/objects", true, ""},
+		{"/foo/1", "0#", 1, ""},
+		{"/foo/1", "0-1#", 0, ""},
+		{"/foo/1", "1#", "foo", ""},
+		{"/highly/nested", "0/objects", true, ""},
+		{"/highly/nested", "1/nested/objects", true, ""},
+		{"/highly/nested", "2/foo/0", "bar", ""},
+		{"/highly/nested", "0#", "nested", ""},
+		{"/highly/nested", "1#", "highly", ""},
+		{"/foo/0", "0+1", "baz", ""},
+		{"/foo/1", "3", nil, "get: cannot ascend 3 levels from /foo/1"},
+		{"/foo/1", "0+1", nil, "get: at /foo: index 2 exceeds array length of 2"},
+		{"/foo/1", "2#", nil, "get: document root has no key"},
+		{"/highly/nested", "0+1", nil, "get: cannot manipulate index of non-array element /highly/nested"},
+		{"/foo/1", "01", nil, "invalid pointer: relative pointer must not have leading zeros"},
+		{"/foo/1", "", nil, "invalid pointer: relative pointer must start with a non-negative integer"},
+		{"/foo/1", "-1", nil, "invalid pointer: relative pointer must start with a non-negative integer"},
+		{"/foo/1", "0+", nil, "invalid pointer: invalid index manipulation in relative pointer"},
+		{"/foo/1", "0+01", nil, "invalid pointer: invalid index manipulation in relative pointer"},
+		{"/foo/1", "0foo", nil, "invalid pointer: non-empty references must begin with a '/' character"},
+		{"/foo/1", "0#/foo", nil, "invalid pointer: non-empty references must begin with a '/' character"},
+	}
+
+	for _, c := range cases {
+		key := c.base + " " + c.rel
+		base, _ := New(c.base)
+		rp, err := NewRelative(c.rel)
+		if err == nil {
+			if rp.String() != c.rel {
+				t.Errorf("%s: expected string: '%s', got: '%s'", key, c.rel, rp.String())
+			}
+			var got interface{}
+			if got, err = rp.Eval(doc, base); err == nil && !reflect.DeepEqual(got, c.expect) {
+				t.Errorf("%s: value mismatch, expected: %#v, got: %#v", key, c.expect, got)
+			}
+		}
+		assertError(t, key, err, c.err)
+	}
+}
@@ -0,0 +1,471 @@
+package jsonpointer
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Resolver resolves JSON pointers against documents. Its fields enable
+// optional behavior; the zero value resolves pointers exactly like
+// Pointer.Get and Pointer.Set do.
+type Resolver struct {
+	// AllocateNil makes Set allocate nil pointers that it encounters while
+	// descending into the document instead of failing. The newly allocated
+	// values are linked into the document, so the nil pointers must be
+	// addressable (e.g. a field of a struct that was passed by pointer).
+	AllocateNil bool
+
+	// AppendAtLength makes Set append the value to a slice if the last token
+	// of the pointer is an index equal to the length of the slice. The slice
+	// must be addressable. Indices beyond the length still cause an error.
+	AppendAtLength bool
+
+	// CheckNumericRange makes Set fail if a numeric value does not fit into
+	// the destination type, e.g. when setting 300 to an int8 or a negative
+	// number to an uint. By default, such values wrap around silently.
+	CheckNumericRange bool
+
+	// CallFuncs makes the resolver call functions of type func() interface{}
+	// or func() (interface{}, error) that it encounters while descending into
+	// the document and continue with the returned value. This enables lazily
+	// computed documents, but may cause side effects.
+	CallFuncs bool
+
+	// MaxResults limits the number of values returned by GetAll. Once the
+	// limit is reached, the expansion stops and ErrTooManyResults is returned
+	// along with the values found so far. Zero means no limit.
+	MaxResults int
+
+	// UnwrapOptionals makes the resolver unwrap values that implement the
+	// Optional interface, so that pointers traverse them transparently. Get
+	// returns the wrapped value, or nil if it is absent.
+	UnwrapOptionals bool
+
+	// MatchSelectors enables selector tokens of the form "[field=value]" on
+	// arrays, which select the first element whose child "field" equals
+	// "value". Strings are compared literally and numbers numerically, so
+	// "/users/[id=42]/name" selects the user with the id 42.
+	MatchSelectors bool
+
+	// NormalizeKeys makes the resolver fall back to a normalized comparison of
+	// tokens and struct field names, if no field matches exactly. Both are
+	// case-folded and stripped of underscores and hyphens, so that tokens in
+	// the style of environment variables like "DB_HOST" match fields like
+	// `json:"dbHost"`. If more than one field matches, resolution fails.
+	NormalizeKeys bool
+
+	// ResolveMarshalers makes the resolver descend into values implementing
+	// json.Marshaler by their JSON structure instead of their Go structure.
+	// The values are marshaled and parsed again, which is costly. Such values
+	// are read-only: Set, Add and Delete fail if they would descend into them.
+	ResolveMarshalers bool
+
+	// DurationUnit is the unit of numeric values converted by GetDuration.
+	// Defaults to nanoseconds.
+	DurationUnit time.Duration
+
+	// NotFoundValue is returned by Get instead of an error, if the pointer
+	// cannot be resolved. This is useful for rendering missing values as a
+	// marker. By default, an error is returned.
+	NotFoundValue interface{}
+
+	// DedupeShared makes Walk visit values that are shared between multiple
+	// locations of the document (e.g. the same map referenced by two keys)
+	// only once, at the location that is visited first.
+	DedupeShared bool
+
+	// OnError is called whenever Get or Set fail, e.g. for logging. It
+	// receives the pointer, the index of the token that could not be resolved
+	// (or the length of the pointer, if setting the resolved value failed) and
+	// the error. The hook is purely observational and does not alter the
+	// returned error.
+	OnError func(ptr Pointer, tokenIndex int, err error)
+
+	// SparseArrays makes GetAll and Children treat maps whose keys are all
+	// array indices (e.g. "0", "1", "10") like arrays, so that their children
+	// are iterated in numeric rather than lexical key order.
+	SparseArrays bool
+
+	// CopyOnGet makes Get return deep copies of the resolved values, so that
+	// modifications of returned maps, slices or pointers do not affect the
+	// document. By default, such values are shared with the document.
+	CopyOnGet bool
+
+	// ScalarsAsArrays treats primitive values as single-element arrays, so
+	// that the token "0" resolves to the value itself. This eases handling of
+	// fields that hold either a single value or an array of values.
+	ScalarsAsArrays bool
+
+	// StrictTags resolves struct fields strictly by the names that
+	// encoding/json uses: the name given by the struct tag (see TagName) or,
+	// if it has none, the Go name of the field. Fields tagged with "-" are
+	// excluded. By default, fields can also be addressed by their Go name.
+	StrictTags bool
+
+	// NonFiniteFloats controls how GetJSON encodes NaN and infinite floats.
+	// By default, encoding them fails.
+	NonFiniteFloats NonFiniteFloats
+
+	// NonFiniteString is the string that NaN and infinite floats are encoded
+	// as with NonFiniteFloats set to NonFiniteString. If empty, the floats
+	// are formatted as "NaN", "+Inf" or "-Inf".
+	NonFiniteString string
+
+	// TagName is the key of the struct tags that give the names of struct
+	// fields, e.g. "yaml". Defaults to "json".
+	TagName string
+
+	// FollowLinks makes Get follow links between locations of the document.
+	// A link is an object whose only member is named LinkKey and holds a
+	// pointer, e.g. {"$ptr": "/other/location"}. When the resolution reaches
+	// a link, it continues at the value that the link points to, which may be
+	// a link itself. Cyclic links cause an error.
+	FollowLinks bool
+
+	// LinkKey is the name of the member of links. Defaults to "$ptr".
+	LinkKey string
+
+	// LoadLazy makes the resolver load nodes that implement the Loader
+	// interface when the resolution reaches them and continue with the loaded
+	// values. Get returns the loaded value of a lazy node. Nodes are loaded
+	// each time they are reached; the loaded values are not cached.
+	LoadLazy bool
+
+	// FloatFormat is the format that Set uses to convert floats and complex
+	// numbers to strings, one of 'b', 'e', 'E', 'f', 'g', 'G', 'x' and 'X'
+	// (see strconv.FormatFloat). It is used along with FloatPrecision. By
+	// default, 'f' is used. Set fails for other formats.
+	FloatFormat byte
+
+	// FloatPrecision is the precision that is used with FloatFormat, e.g. 6
+	// for six significant digits with 'g'. It only applies if FloatFormat is
+	// set. Zero, the default, uses the smallest precision that represents the
+	// value exactly, so rounding to zero digits is not supported.
+	FloatPrecision int
+}
+
+const (
+	defaultTagName = "json"
+	defaultLinkKey = "$ptr"
+)
+
+var defaultResolver = &Resolver{}
+
+// tagName returns the key of the struct tags that give the names of fields.
+func (r *Resolver) tagName() string {
+	if r.TagName == "" {
+		return defaultTagName
+	}
+	return r.TagName
+}
+
+// floatFormat returns the format and precision for converting floats to
+// strings.
+func (r *Resolver) floatFormat() (byte, int, error) {
+	format, prec := r.FloatFormat, -1
+	switch format {
+	case 0:
+		format = 'f'
+	case 'b', 'e', 'E', 'f', 'g', 'G', 'x', 'X':
+	default:
+		return 0, 0, newError(ErrSet, "invalid float format '%c'", format)
+	}
+	if r.FloatFormat != 0 && r.FloatPrecision > 0 {
+		prec = r.FloatPrecision
+	}
+	return format, prec, nil
+}
+
+// linkKey returns the name of the member of links.
+func (r *Resolver) linkKey() string {
+	if r.LinkKey == "" {
+		return defaultLinkKey
+	}
+	return r.LinkKey
+}
+
+// Get returns the value from the given document that the pointer points to.
+func (r *Resolver) Get(p Pointer, doc interface{}) (interface{}, error) {
+	return r.GetTraced(p, doc, nil)
+}
+
+// GetTraced is like Get, but invokes trace before resolving each token of the
+// pointer. The trace function receives the index of the token, the token
+// itself and the kind of the container the token is resolved in.
+func (r *Resolver) GetTraced(p Pointer, doc interface{}, trace func(tokenIndex int, token string, kind reflect.Kind)) (interface{}, error) {
+	resultVal, i, err := r.resolve(p, doc, trace)
+	if err != nil {
+		if r.NotFoundValue != nil && errors.Is(err, ErrGetFailed) {
+			return r.NotFoundValue, nil
+		}
+		err = atToken(err, p, i)
+		r.reportError(p, i, err)
+		return nil, err
+	}
+	if r.LoadLazy {
+		if l, ok := asLoader(resultVal); ok {
+			if resultVal, err = load(l); err != nil {
+				err = atToken(err, p, len(p))
+				r.reportError(p, len(p), err)
+				return nil, err
+			}
+			if !resultVal.IsValid() {
+				return nil, nil
+			}
+		}
+	}
+	if r.UnwrapOptionals {
+		if o, ok := asOptional(resultVal); ok {
+			if val, present := o.Get(); present {
+				return r.copyResult(val), nil
+			}
+			return nil, nil
+		}
+	}
+	if !resultVal.CanInterface() {
+		err = newError(ErrGet, "cannot get document value")
+		r.reportError(p, len(p), err)
+		return nil, err
+	}
+	return r.copyResult(resultVal.Interface()), nil
+}
+
+// Resolve returns the value that the pointer points to as reflect.Value, like
+// Get does, but without converting it to an interface{}. This allows to inspect
+// and modify the value without resolving the pointer twice. Lazy nodes and
+// optional values at the end of the pointer are not unwrapped.
+//
+// The value is addressable, and thus settable if it is exported, if it is
+// reached through a pointer or a slice without passing through a map or an
+// interface, e.g. a field of a struct that was passed by pointer or an element
+// of a slice. Map elements and values held by interfaces are never
+// addressable.
+func (r *Resolver) Resolve(p Pointer, doc interface{}) (reflect.Value, error) {
+	resultVal, i, err := r.resolve(p, doc, nil)
+	if err != nil {
+		err = atToken(err, p, i)
+		r.reportError(p, i, err)
+		return reflect.Value{}, err
+	}
+	return resultVal, nil
+}
+
+// Probe checks whether the pointer can be resolved against the document. It
+// returns nil if so, or the resolution error otherwise. Unlike Get, the
+// resolved value is not retrieved, so Resolver.NotFoundValue does not apply.
+func (r *Resolver) Probe(p Pointer, doc interface{}) error {
+	_, err := r.Resolve(p, doc)
+	return err
+}
+
+// Exists reports whether the pointer can be resolved against the document,
+// i.e. whether Probe returns nil. The empty pointer always exists.
+func (r *Resolver) Exists(p Pointer, doc interface{}) bool {
+	return r.Probe(p, doc) == nil
+}
+
+// resolve walks the document along the pointer and returns the value that it
+// points to. On failure, the index of the token that could not be resolved is
+// returned along with the error.
+func (r *Resolver) resolve(p Pointer, doc interface{}, trace func(tokenIndex int, token string, kind reflect.Kind)) (reflect.Value, int, error) {
+	var (
+		err       error
+		following map[string]bool
+	)
+	resultVal := reflect.ValueOf(doc)
+	if r.FollowLinks {
+		following = map[string]bool{}
+	}
+	for i, part := range p {
+		if trace != nil {
+			trace(i, part, indirect(resultVal).Kind())
+		}
+		resultVal, err = r.getValue(resultVal, part)
+		if err == nil && r.FollowLinks {
+			resultVal, err = r.followLink(reflect.ValueOf(doc), resultVal, following)
+		}
+		if err != nil {
+			return reflect.Value{}, i, err
+		}
+	}
+	return resultVal, len(p), nil
+}
+
+// copyResult returns a deep copy of the value, if Resolver.CopyOnGet is set.
+func (r *Resolver) copyResult(value interface{}) interface{} {
+	if !r.CopyOnGet || value == nil {
+		return value
+	}
+	return deepCopy(reflect.ValueOf(value)).Interface()
+}
+
+// followLink resolves the value that the link points to, if the value is a
+// link. Links are resolved against the document root. The pointers of the
+// links that are currently followed are tracked to detect cycles.
+func (r *Resolver) followLink(root, val reflect.Value, following map[string]bool) (reflect.Value, error) {
+	target, ok := r.linkTarget(val)
+	if !ok {
+		return val, nil
+	}
+	if following[target] {
+		return reflect.Value{}, newError(ErrGet, "cyclic link '%s'", target)
+	}
+	p, err := defaultDialect.parse(target)
+	if err != nil {
+		return reflect.Value{}, wrapError(err, ErrGet, "invalid link '%s': %s", target, err)
+	}
+
+	following[target] = true
+	defer delete(following, target)
+	resultVal := root
+	for _, part := range p {
+		if resultVal, err = r.getValue(resultVal, part); err != nil {
+			return reflect.Value{}, err
+		}
+		if resultVal, err = r.followLink(root, resultVal, following); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return resultVal, nil
+}
+
+// linkTarget returns the pointer of the link, if the value is a map whose only
+// key is the link key with a string value.
+func (r *Resolver) linkTarget(val reflect.Value) (string, bool) {
+	val = indirect(val)
+	if val.Kind() != reflect.Map || val.Type().Key().Kind() != reflect.String || val.Len() != 1 {
+		return "", false
+	}
+	target := indirect(val.MapIndex(reflect.ValueOf(r.linkKey()).Convert(val.Type().Key())))
+	if target.Kind() != reflect.String {
+		return "", false
+	}
+	return target.String(), true
+}
+
+// Set sets the value at the given pointer in the given document.
+//
+// Elements of maps are not addressable. A map element itself is replaced by
+// storing the new value in the map. To set a value inside a struct or array
+// that is stored in a map, the element is copied, modified and stored back.
+func (r *Resolver) Set(p Pointer, doc interface{}, value interface{}) error {
+	docVal := reflect.ValueOf(doc)
+	if docVal.Kind() == reflect.Array && len(p) > 0 {
+		err := newError(ErrSet, "cannot set value in array of type %s passed by value", docVal.Type())
+		r.reportError(p, 0, err)
+		return err
+	}
+	if i, err := r.set(docVal, p, 0, value); err != nil {
+		err = atToken(err, p, i)
+		r.reportError(p, i, err)
+		return err
+	}
+	return nil
+}
+
+// set sets the value at the remaining tokens p[i:] of the pointer. On failure,
+// it returns the index of the failing token along with the error.
+func (r *Resolver) set(docVal reflect.Value, p Pointer, i int, value interface{}) (int, error) {
+	if i == len(p) {
+		// set value to pointer
+		return i, r.setValue(docVal, value)
+	}
+
+	if err := r.checkMutable(docVal); err != nil {
+		return i, err
+	}
+	if r.AllocateNil {
+		allocateNil(docVal)
+	}
+	if r.AppendAtLength && i == len(p)-1 {
+		if ok, err := r.appendAtLength(docVal, p[i], value); ok {
+			return i, err
+		}
+	}
+	childVal, err := r.getValue(docVal, p[i])
+	if err != nil {
+		return i, err
+	}
+
+	// replace an unaddressable map element by storing a new value in the map
+	if mapVal := indirect(docVal); mapVal.Kind() == reflect.Map && i == len(p)-1 && !childVal.CanSet() {
+		keyVal, err := mapKey(mapVal, p[i])
+		if err != nil {
+			return i, err
+		}
+		elemVal := reflect.New(mapVal.Type().Elem()).Elem()
+		if err := r.setValue(elemVal, value); err != nil {
+			return len(p), err
+		}
+		mapVal.SetMapIndex(keyVal, elemVal)
+		return len(p), nil
+	}
+
+	// modify a copy of an unaddressable map element and store it back
+	if mapVal := indirect(docVal); mapVal.Kind() == reflect.Map && i < len(p)-1 && !childVal.CanSet() {
+		elemVal := childVal
+		if elemVal.Kind() == reflect.Interface {
+			elemVal = elemVal.Elem()
+		}
+		if elemVal.Kind() == reflect.Struct || elemVal.Kind() == reflect.Array {
+			tmpVal := reflect.New(elemVal.Type()).Elem()
+			tmpVal.Set(elemVal)
+			if j, err := r.set(tmpVal, p, i+1, value); err != nil {
+				return j, err
+			}
+			keyVal, err := mapKey(mapVal, p[i])
+			if err != nil {
+				return i, err
+			}
+			mapVal.SetMapIndex(keyVal, tmpVal)
+			return i, nil
+		}
+	}
+
+	return r.set(childVal, p, i+1, value)
+}
+
+// reportError passes a resolution failure to the OnError hook, if set.
+func (r *Resolver) reportError(p Pointer, tokenIndex int, err error) {
+	if r.OnError != nil {
+		r.OnError(p, tokenIndex, err)
+	}
+}
+
+// allocateNil allocates a new value for a nil pointer and links it into the
+// document. Unaddressable values are left untouched.
+func allocateNil(val reflect.Value) {
+	if val.Kind() == reflect.Pointer && val.IsNil() && val.CanSet() {
+		val.Set(reflect.New(val.Type().Elem()))
+	}
+}
+
+// appendAtLength appends the value to the given slice if the key is an index
+// equal to the length of the slice. It reports whether the key addressed the
+// end of the slice.
+func (r *Resolver) appendAtLength(doc reflect.Value, key string, value interface{}) (bool, error) {
+	for (doc.Kind() == reflect.Pointer || doc.Kind() == reflect.Interface) && !doc.IsNil() {
+		doc = doc.Elem()
+	}
+	if doc.Kind() != reflect.Slice {
+		return false, nil
+	}
+	if i, err := strconv.Atoi(key); err != nil || i != doc.Len() {
+		return false, nil
+	}
+	if !doc.CanSet() {
+		return true, newError(ErrSet, "cannot append to unaddressable array")
+	}
+
+	elem := reflect.New(doc.Type().Elem()).Elem()
+	srcVal := reflect.ValueOf(value)
+	if elem.Kind() == reflect.Interface && srcVal.IsValid() && srcVal.Type().AssignableTo(elem.Type()) {
+		elem.Set(srcVal)
+	} else if err := r.setValue(elem, value); err != nil {
+		return true, err
+	}
+	doc.Set(reflect.Append(doc, elem))
+	return true, nil
+}
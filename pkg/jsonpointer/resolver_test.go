@@ -0,0 +1,868 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestResolverAllocateNil(t *testing.T) {
+	type inner struct {
+		Name string `json:"name"`
+	}
+	type outer struct {
+		Inner *inner `json:"inner"`
+	}
+	ptr, _ := New("/inner/name")
+
+	doc := outer{}
+	if err := ptr.Set(&doc, "foo"); err == nil {
+		t.Errorf("expected an error when setting through a nil pointer")
+	}
+	if doc.Inner != nil {
+		t.Errorf("expected nil pointer to remain untouched")
+	}
+
+	r := Resolver{AllocateNil: true}
+	if err := r.Set(ptr, &doc, "foo"); err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if doc.Inner == nil {
+		t.Fatalf("expected nil pointer to be allocated")
+	}
+	if doc.Inner.Name != "foo" {
+		t.Errorf("expected value 'foo', got: '%s'", doc.Inner.Name)
+	}
+
+	got, err := r.Get(ptr, doc)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if got != "foo" {
+		t.Errorf("expected value 'foo', got: %#v", got)
+	}
+}
+
+func TestGetTraced(t *testing.T) {
+	type step struct {
+		index int
+		token string
+		kind  reflect.Kind
+	}
+	type item struct {
+		Name string `json:"name"`
+	}
+	doc := map[string]interface{}{
+		"items": []item{{Name: "foo"}, {Name: "bar"}},
+	}
+	ptr, _ := New("/items/1/name")
+
+	var steps []step
+	got, err := ptr.GetTraced(doc, func(i int, tok string, kind reflect.Kind) {
+		steps = append(steps, step{i, tok, kind})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if got != "bar" {
+		t.Errorf("expected value 'bar', got: %#v", got)
+	}
+
+	expect := []step{
+		{0, "items", reflect.Map},
+		{1, "1", reflect.Slice},
+		{2, "name", reflect.Struct},
+	}
+	if !reflect.DeepEqual(steps, expect) {
+		t.Errorf("trace mismatch, expected: %v, got: %v", expect, steps)
+	}
+
+	// trace stops at the failing token
+	steps = nil
+	ptr, _ = New("/items/5/name")
+	if _, err := ptr.GetTraced(doc, func(i int, tok string, kind reflect.Kind) {
+		steps = append(steps, step{i, tok, kind})
+	}); err == nil {
+		t.Errorf("expected an error")
+	}
+	if len(steps) != 2 {
+		t.Errorf("expected 2 trace steps, got: %v", steps)
+	}
+}
+
+func TestResolverAppendAtLength(t *testing.T) {
+	type document struct {
+		Tags  []string      `json:"tags"`
+		Items []interface{} `json:"items"`
+	}
+	doc := document{Tags: []string{"a", "b"}, Items: []interface{}{1}}
+	r := Resolver{AppendAtLength: true}
+
+	cases := []struct {
+		ptrstring string
+		value     interface{}
+		err       string
+	}{
+		{"/tags/2", "c", ""},
+		{"/tags/3", "d", ""},
+		{"/tags/5", "e", "get: at /tags: index 5 exceeds array length of 4"},
+		{"/items/1", "foo", ""},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		assertError(t, c.ptrstring, r.Set(ptr, &doc, c.value), c.err)
+	}
+
+	if expect := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(doc.Tags, expect) {
+		t.Errorf("expected: %v, got: %v", expect, doc.Tags)
+	}
+	if expect := []interface{}{1, "foo"}; !reflect.DeepEqual(doc.Items, expect) {
+		t.Errorf("expected: %v, got: %v", expect, doc.Items)
+	}
+
+	// appending is opt-in
+	ptr, _ := New("/tags/4")
+	if err := ptr.Set(&doc, "e"); err == nil {
+		t.Errorf("expected an error without AppendAtLength")
+	}
+
+	// appending requires an addressable slice
+	if err := r.Set(ptr, doc, "e"); err == nil {
+		t.Errorf("expected an error for an unaddressable slice")
+	}
+}
+
+func TestResolverCheckNumericRange(t *testing.T) {
+	type document struct {
+		Int8    int8    `json:"int8"`
+		Uint8   uint8   `json:"uint8"`
+		Int64   int64   `json:"int64"`
+		Uint64  uint64  `json:"uint64"`
+		Float32 float32 `json:"float32"`
+	}
+
+	cases := []struct {
+		ptrstring string
+		value     interface{}
+		err       string
+	}{
+		{"/int8", 127, ""},
+		{"/int8", -128, ""},
+		{"/int8", float64(300), "set: value out of range (float64 ➜ int8)"},
+		{"/int8", 128, "set: value out of range (int ➜ int8)"},
+		{"/int8", "-129", "set: value out of range (string ➜ int8)"},
+		{"/uint8", 255, ""},
+		{"/uint8", uint16(256), "set: value out of range (uint16 ➜ uint8)"},
+		{"/uint8", -1, "set: value out of range (int ➜ uint8)"},
+		{"/uint8", float64(-0.5), "set: value out of range (float64 ➜ uint8)"},
+		{"/int64", uint64(1 << 63), "set: value out of range (uint64 ➜ int64)"},
+		{"/int64", float64(1 << 63), "set: value out of range (float64 ➜ int64)"},
+		{"/uint64", float64(1 << 63), ""},
+		{"/float32", float64(1e39), "set: value out of range (float64 ➜ float32)"},
+		{"/float32", float64(1.5), ""},
+	}
+
+	r := Resolver{CheckNumericRange: true}
+	for _, c := range cases {
+		doc := document{}
+		ptr, _ := New(c.ptrstring)
+		assertError(t, fmt.Sprintf("%s=%v", c.ptrstring, c.value), r.Set(ptr, &doc, c.value), c.err)
+	}
+
+	// values wrap around by default
+	doc := document{}
+	ptr, _ := New("/int8")
+	if err := ptr.Set(&doc, float64(300)); err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if doc.Int8 != 44 {
+		t.Errorf("expected wrapped value 44, got: %d", doc.Int8)
+	}
+	ptr, _ = New("/uint8")
+	if err := ptr.Set(&doc, 256); err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if doc.Uint8 != 0 {
+		t.Errorf("expected wrapped value 0, got: %d", doc.Uint8)
+	}
+}
+
+func TestResolverCallFuncs(t *testing.T) {
+	calls := 0
+	doc := map[string]interface{}{
+		"computed": func() interface{} {
+			calls++
+			return map[string]interface{}{"foo": []interface{}{"bar"}}
+		},
+		"failing": func() (interface{}, error) {
+			return nil, errors.New("boom")
+		},
+		"nil": (func() interface{})(nil),
+	}
+
+	ptr, _ := New("/computed/foo/0")
+	if _, err := ptr.Get(doc); err == nil {
+		t.Errorf("expected an error without CallFuncs")
+	}
+	if calls != 0 {
+		t.Errorf("expected function not to be called without CallFuncs")
+	}
+
+	r := Resolver{CallFuncs: true}
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/computed/foo/0", "bar", ""},
+		{"/failing/foo", nil, "get: at /failing: failed to compute document value: boom"},
+		{"/nil/foo", nil, "get: at /nil: document value is nil"},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected function to be called once, got: %d", calls)
+	}
+}
+
+type testOptional[T any] struct {
+	Value T
+	Valid bool
+}
+
+func (o testOptional[T]) Get() (interface{}, bool) {
+	return o.Value, o.Valid
+}
+
+func TestResolverUnwrapOptionals(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type user struct {
+		Address  testOptional[address] `json:"address"`
+		Nickname testOptional[string]  `json:"nickname"`
+	}
+	doc := map[string]interface{}{
+		"present": user{
+			Address:  testOptional[address]{Value: address{City: "Berlin"}, Valid: true},
+			Nickname: testOptional[string]{Value: "bob", Valid: true},
+		},
+		"absent": user{},
+	}
+
+	ptr, _ := New("/present/address/city")
+	if _, err := ptr.Get(doc); err == nil {
+		t.Errorf("expected an error without UnwrapOptionals")
+	}
+
+	r := Resolver{UnwrapOptionals: true}
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/present/address/city", "Berlin", ""},
+		{"/present/nickname", "bob", ""},
+		{"/absent/address/city", nil, "get: at /absent/address: optional value is absent"},
+		{"/absent/nickname", nil, ""},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestResolverNormalizeKeys(t *testing.T) {
+	type database struct {
+		DBHost  string `json:"dbHost"`
+		MaxConn int    `json:"max_conn"`
+		User    string
+		Port    int `json:"port"`
+		PORT    int `json:"PORT"`
+	}
+	doc := map[string]interface{}{
+		"database": database{DBHost: "localhost", MaxConn: 10, User: "admin", Port: 1, PORT: 2},
+	}
+
+	ptr, _ := New("/database/DB_HOST")
+	if _, err := ptr.Get(doc); err == nil {
+		t.Errorf("expected an error without NormalizeKeys")
+	}
+
+	r := Resolver{NormalizeKeys: true}
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/database/DB_HOST", "localhost", ""},
+		{"/database/db-host", "localhost", ""},
+		{"/database/MAX_CONN", 10, ""},
+		{"/database/maxConn", 10, ""},
+		{"/database/USER", "admin", ""},
+		{"/database/port", 1, ""},
+		{"/database/Port", 1, ""},
+		{"/database/P_O_R_T", nil, "get: at /database: struct field 'P_O_R_T' is ambiguous"},
+		{"/database/DB_PORT", nil, "get: at /database: struct has no field 'DB_PORT'"},
+		{"/database/", nil, "get: at /database: struct has no field ''"},
+		{"/database/_", nil, "get: at /database: struct has no field '_'"},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+type testMoney struct {
+	cents    int
+	currency string
+}
+
+func (m testMoney) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"amount":   float64(m.cents) / 100,
+		"currency": m.currency,
+	})
+}
+
+type testBroken struct{}
+
+func (testBroken) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("boom")
+}
+
+func TestResolverResolveMarshalers(t *testing.T) {
+	type order struct {
+		Total  testMoney  `json:"total"`
+		Broken testBroken `json:"broken"`
+	}
+	doc := order{Total: testMoney{cents: 1250, currency: "EUR"}}
+
+	ptr, _ := New("/total/amount")
+	if _, err := ptr.Get(doc); err == nil {
+		t.Errorf("expected an error without ResolveMarshalers")
+	}
+
+	r := Resolver{ResolveMarshalers: true}
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{"/total/amount", 12.5, ""},
+		{"/total/currency", "EUR", ""},
+		{"/total/cents", nil, "get: at /total: map has no key 'cents'"},
+		{"/broken/foo", nil, "get: at /broken: failed to marshal document value: boom"},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// values resolved through json.Marshaler cannot be modified
+	orig := doc
+	ptr, _ = New("/total/amount")
+	err := r.Set(ptr, &doc, 5.0)
+	assertError(t, "set", err, "set: at /total: cannot modify value resolved through json.Marshaler")
+	if !errors.Is(err, ErrSetFailed) {
+		t.Errorf("set: expected error to match %q", ErrSetFailed)
+	}
+	err = r.Add(ptr, &doc, 5.0)
+	assertError(t, "add", err, "set: cannot modify value resolved through json.Marshaler")
+	err = r.Delete(ptr, &doc)
+	assertError(t, "delete", err, "set: cannot modify value resolved through json.Marshaler")
+	if doc != orig {
+		t.Errorf("document was modified: %#v", doc)
+	}
+
+	// the value itself can still be replaced
+	ptr, _ = New("/total")
+	if err := r.Set(ptr, &doc, testMoney{cents: 500, currency: "USD"}); err != nil {
+		t.Errorf("%s: expected no error, got: %s", ptr, err)
+	}
+	if doc.Total.cents != 500 {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, 500, doc.Total.cents)
+	}
+}
+
+func TestSetMapOfStructs(t *testing.T) {
+	type tls struct {
+		Enabled bool `json:"enabled"`
+	}
+	type server struct {
+		Host  string `json:"host"`
+		Port  int    `json:"port"`
+		TLS   tls    `json:"tls"`
+		Ports [2]int `json:"ports"`
+	}
+	doc := struct {
+		Servers map[string]server      `json:"servers"`
+		Generic map[string]interface{} `json:"generic"`
+	}{
+		Servers: map[string]server{
+			"web": {Host: "localhost", Port: 80},
+			"db":  {Host: "localhost", Port: 5432},
+		},
+		Generic: map[string]interface{}{
+			"web": server{Host: "localhost", Port: 80},
+		},
+	}
+
+	cases := []struct {
+		ptrstring string
+		value     interface{}
+		err       string
+	}{
+		{"/servers/web/port", 8080, ""},
+		{"/servers/web/tls/enabled", true, ""},
+		{"/servers/db/ports/1", 5433, ""},
+		{"/generic/web/port", 8081, ""},
+		{"/servers/web/missing", 1, "get: at /servers/web: struct has no field 'missing'"},
+		{"/servers/mail/port", 25, "get: at /servers: map has no key 'mail'"},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		assertError(t, c.ptrstring, ptr.Set(&doc, c.value), c.err)
+	}
+
+	expect := map[string]server{
+		"web": {Host: "localhost", Port: 8080, TLS: tls{Enabled: true}},
+		"db":  {Host: "localhost", Port: 5432, Ports: [2]int{0, 5433}},
+	}
+	if !reflect.DeepEqual(doc.Servers, expect) {
+		t.Errorf("expected: %+v, got: %+v", expect, doc.Servers)
+	}
+	if got := doc.Generic["web"].(server).Port; got != 8081 {
+		t.Errorf("expected port 8081, got: %d", got)
+	}
+}
+
+func TestResolverNotFoundValue(t *testing.T) {
+	doc := map[string]interface{}{
+		"foo": []interface{}{"bar"},
+		"nil": nil,
+	}
+	const marker = "<missing>"
+	r := Resolver{NotFoundValue: marker}
+
+	cases := []struct {
+		ptrstring string
+		expect    interface{}
+	}{
+		{"/foo/0", "bar"},
+		{"/nil", nil},
+		{"/foo/1", marker},
+		{"/foo/bar", marker},
+		{"/missing/deep", marker},
+		{"/nil/deep", marker},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.ptrstring, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// no injection by default
+	ptr, _ := New("/missing")
+	if _, err := ptr.Get(doc); err == nil {
+		t.Errorf("expected an error without NotFoundValue")
+	}
+}
+
+func TestResolverOnError(t *testing.T) {
+	type failure struct {
+		ptr        string
+		tokenIndex int
+		err        string
+	}
+	var failures []failure
+	r := Resolver{OnError: func(ptr Pointer, tokenIndex int, err error) {
+		failures = append(failures, failure{ptr.String(), tokenIndex, err.Error()})
+	}}
+	doc := map[string]interface{}{
+		"foo": map[string]interface{}{"bar": []interface{}{1}},
+	}
+
+	ptr, _ := New("/foo/bar/3/baz")
+	_, err := r.Get(ptr, doc)
+	assertError(t, ptr.String(), err, "get: at /foo/bar: index 3 exceeds array length of 1")
+
+	ptr, _ = New("/foo/qux")
+	err = r.Set(ptr, doc, 1)
+	assertError(t, ptr.String(), err, "get: at /foo: map has no key 'qux'")
+
+	ptr, _ = New("/foo/bar/0")
+	if _, err := r.Get(ptr, doc); err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+
+	expect := []failure{
+		{"/foo/bar/3/baz", 2, "get: at /foo/bar: index 3 exceeds array length of 1"},
+		{"/foo/qux", 1, "get: at /foo: map has no key 'qux'"},
+	}
+	if !reflect.DeepEqual(failures, expect) {
+		t.Errorf("expected: %v, got: %v", expect, failures)
+	}
+}
+
+func TestResolverCopyOnGet(t *testing.T) {
+	ptr, _ := New("/config")
+
+	for _, copyOnGet := range []bool{false, true} {
+		doc := map[string]interface{}{
+			"config": map[string]interface{}{
+				"tags": []interface{}{"a", "b"},
+			},
+		}
+		r := Resolver{CopyOnGet: copyOnGet}
+		value, err := r.Get(ptr, doc)
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		config := value.(map[string]interface{})
+		config["added"] = 1
+		config["tags"].([]interface{})[0] = "z"
+
+		_, added := doc["config"].(map[string]interface{})["added"]
+		tag := doc["config"].(map[string]interface{})["tags"].([]interface{})[0]
+		if added != !copyOnGet || (tag == "z") != !copyOnGet {
+			t.Errorf("CopyOnGet=%t: expected document to be modified: %t", copyOnGet, !copyOnGet)
+		}
+	}
+
+	// the copy is complete
+	r := Resolver{CopyOnGet: true}
+	doc := map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b": 2}}}
+	value, _ := r.Get(Pointer{}, doc)
+	if !reflect.DeepEqual(value, doc) {
+		t.Errorf("value mismatch, expected: %#v, got: %#v", doc, value)
+	}
+}
+
+func TestResolverScalarsAsArrays(t *testing.T) {
+	doc := map[string]interface{}{
+		"single": "a",
+		"multi":  []interface{}{"a", "b"},
+	}
+
+	cases := []struct {
+		ptrstring string
+		scalars   bool
+		expect    interface{}
+		err       string
+	}{
+		{"/single/0", true, "a", ""},
+		{"/multi/1", true, "b", ""},
+		{"/single/1", true, nil, "get: at /single: index 1 exceeds array length of 1"},
+		{"/single/foo", true, nil, "get: at /single: cannot resolve token 'foo' in primitive value of type string"},
+		{"/single/0", false, nil, "get: at /single: cannot resolve token '0' in primitive value of type string"},
+	}
+
+	for _, c := range cases {
+		r := Resolver{ScalarsAsArrays: c.scalars}
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+type celsius float64
+
+func (c celsius) String() string { return fmt.Sprintf("%.1f°C", float64(c)) }
+
+func TestSetInterfaceFields(t *testing.T) {
+	type Sensor struct {
+		Reading fmt.Stringer
+		Meta    interface{}
+	}
+	doc := &Sensor{Reading: celsius(20), Meta: "foo"}
+	list := []interface{}{1, "a"}
+
+	cases := []struct {
+		ptrstring string
+		doc       interface{}
+		value     interface{}
+		expect    interface{}
+		err       string
+	}{
+		{"/Reading", doc, celsius(21.5), celsius(21.5), ""},
+		{"/Reading", doc, 21.5, nil, "set: value of type float64 does not implement fmt.Stringer"},
+		{"/Reading", doc, nil, nil, ""},
+		{"/Meta", doc, map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1}, ""},
+		{"/Meta", doc, 1, 1, ""},
+		{"/1", list, 2.5, 2.5, ""},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		err := ptr.Set(c.doc, c.value)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		got, _ := ptr.Get(c.doc)
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestResolverStrictTags(t *testing.T) {
+	type Account struct {
+		Name     string `json:"name,omitempty"`
+		Email    string `json:",omitempty"`
+		Password string `json:"-"`
+		Dash     string `json:"-,"`
+		Plain    string
+		secret   string
+	}
+	doc := Account{Name: "n", Email: "e", Password: "p", Dash: "d", Plain: "x", secret: "s"}
+
+	cases := []struct {
+		ptrstring string
+		strict    bool
+		expect    interface{}
+		err       string
+	}{
+		{"/name", true, "n", ""},
+		{"/Name", true, nil, "get: struct has no field 'Name'"},
+		{"/Name", false, "n", ""},
+		{"/Email", true, "e", ""},
+		{"/Password", true, nil, "get: struct has no field 'Password'"},
+		{"/Password", false, nil, "get: struct has no field 'Password'"},
+		{"/-", true, "d", ""},
+		{"/Dash", true, nil, "get: struct has no field 'Dash'"},
+		{"/Plain", true, "x", ""},
+		{"/secret", true, nil, "get: struct has no field 'secret'"},
+	}
+
+	for _, c := range cases {
+		r := Resolver{StrictTags: c.strict}
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestResolverTagName(t *testing.T) {
+	type Database struct {
+		Host string `yaml:"host" json:"hostname"`
+		Port int    `yaml:"port,omitempty"`
+		Pass string `yaml:"-"`
+	}
+	type Config struct {
+		Database Database `yaml:"database"`
+	}
+	doc := &Config{Database: Database{Host: "localhost", Port: 5432, Pass: "secret"}}
+
+	cases := []struct {
+		ptrstring string
+		tagName   string
+		strict    bool
+		expect    interface{}
+		err       string
+	}{
+		{"/database/host", "yaml", false, "localhost", ""},
+		{"/database/port", "yaml", false, 5432, ""},
+		{"/Database/Host", "yaml", false, "localhost", ""},
+		{"/database/hostname", "yaml", false, nil, "get: at /database: struct has no field 'hostname'"},
+		{"/database/pass", "yaml", true, nil, "get: at /database: struct has no field 'pass'"},
+		{"/Database/hostname", "", false, "localhost", ""},
+		{"/database/host", "", false, nil, "get: struct has no field 'database'"},
+	}
+
+	for _, c := range cases {
+		r := Resolver{TagName: c.tagName, StrictTags: c.strict}
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// values can be set using the tag names
+	r := Resolver{TagName: "yaml"}
+	ptr, _ := New("/database/port")
+	if err := r.Set(ptr, doc, 5433); err != nil {
+		t.Errorf("%s: expected no error, got: %s", ptr, err)
+	}
+	if doc.Database.Port != 5433 {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, 5433, doc.Database.Port)
+	}
+
+	// walked pointers use the tag names and resolve with the same resolver
+	var walked []string
+	err := r.Walk(doc, func(p Pointer, value interface{}) error {
+		walked = append(walked, p.String())
+		if _, err := r.Get(p, doc); err != nil {
+			t.Errorf("%s: expected no error, got: %s", p, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+	expectWalked := []string{"", "/database", "/database/host", "/database/port"}
+	if !reflect.DeepEqual(walked, expectWalked) {
+		t.Errorf("walk mismatch, expected: %v, got: %v", expectWalked, walked)
+	}
+	ptrs, err := r.Children(Pointer{"database"}, doc)
+	if err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+	if len(ptrs) != 2 || ptrs[0].String() != "/database/host" {
+		t.Errorf("children mismatch, got: %v", ptrs)
+	}
+
+	// values can be deleted using the tag names
+	ptr, _ = New("/database/host")
+	if err := r.Delete(ptr, doc); err != nil {
+		t.Errorf("%s: expected no error, got: %s", ptr, err)
+	}
+	if doc.Database.Host != "" {
+		t.Errorf("%s: value mismatch, expected: %#v, got: %#v", ptr, "", doc.Database.Host)
+	}
+}
+
+func TestResolverFollowLinks(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{
+		"defaults": {"db": {"host": "localhost", "port": 5432}},
+		"primary": {"$ptr": "/defaults/db"},
+		"replica": {"$ptr": "/primary"},
+		"hosts": [{"$ptr": "/defaults/db/host"}],
+		"self": {"$ptr": "/self"},
+		"ping": {"$ptr": "/pong/next"},
+		"pong": {"next": {"$ptr": "/ping"}},
+		"broken": {"$ptr": "/missing"},
+		"invalid": {"$ptr": "foo"},
+		"plain": {"$ptr": "/defaults", "other": 1},
+		"custom": {"@ref": "/defaults/db/port"}
+	}`), &doc)
+
+	cases := []struct {
+		ptrstring string
+		linkKey   string
+		expect    interface{}
+		err       string
+	}{
+		{"/primary/host", "", "localhost", ""},
+		{"/replica/port", "", 5432.0, ""},
+		{"/replica", "", map[string]interface{}{"host": "localhost", "port": 5432.0}, ""},
+		{"/hosts/0", "", "localhost", ""},
+		{"/self", "", nil, "get: cyclic link '/self'"},
+		{"/ping/next", "", nil, "get: cyclic link '/pong/next'"},
+		{"/broken", "", nil, "get: map has no key 'missing'"},
+		{"/invalid", "", nil, "get: invalid link 'foo': invalid pointer: non-empty references must begin with a '/' character"},
+		{"/plain/other", "", 1.0, ""},
+		{"/custom", "@ref", 5432.0, ""},
+		{"/custom/@ref", "", "/defaults/db/port", ""},
+	}
+
+	for _, c := range cases {
+		r := Resolver{FollowLinks: true, LinkKey: c.linkKey}
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+
+	// links are not followed by default
+	ptr, _ := New("/primary/host")
+	_, err := ptr.Get(doc)
+	assertError(t, ptr.String(), err, "get: at /primary: map has no key 'host'")
+}
+
+func TestResolverFloatFormat(t *testing.T) {
+	type document struct {
+		Str string `json:"str"`
+	}
+
+	cases := []struct {
+		format    byte
+		precision int
+		value     interface{}
+		expect    string
+		err       string
+	}{
+		{0, 0, 0.1, "0.1", ""},
+		{0, 0, float32(0.1), "0.10000000149011612", ""},
+		{0, 0, 1e21, "1000000000000000000000", ""},
+		{0, 0, complex(1.5, 2), "(1.5+2i)", ""},
+		{0, 2, 0.125, "0.125", ""},
+		{'g', 0, 3.14159, "3.14159", ""},
+		{'f', 0, 3.14159, "3.14159", ""},
+		{'g', 6, float32(0.1), "0.1", ""},
+		{'g', 3, 3.14159, "3.14", ""},
+		{'g', -1, 1e21, "1e+21", ""},
+		{'e', 2, 1234.5678, "1.23e+03", ""},
+		{'f', 2, 0.125, "0.12", ""},
+		{'f', 2, complex(1.5, 2), "(1.50+2.00i)", ""},
+		{'g', 3, 42, "42", ""},
+		{'q', 0, 3.14159, "", "set: invalid float format 'q'"},
+		{'q', 0, complex(1.5, 2), "", "set: invalid float format 'q'"},
+	}
+
+	for _, c := range cases {
+		r := Resolver{FloatFormat: c.format, FloatPrecision: c.precision}
+		doc := document{}
+		key := fmt.Sprintf("%q/%d/%v", c.format, c.precision, c.value)
+		err := r.Set(Pointer{"str"}, &doc, c.value)
+		if assertError(t, key, err, c.err) {
+			continue
+		}
+		if doc.Str != c.expect {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", key, c.expect, doc.Str)
+		}
+	}
+}
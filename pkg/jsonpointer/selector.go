@@ -0,0 +1,56 @@
+package jsonpointer
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseSelector parses a selector token of the form "[field=value]".
+func parseSelector(tok string) (field, value string, ok bool) {
+	if len(tok) < 3 || tok[0] != '[' || tok[len(tok)-1] != ']' {
+		return "", "", false
+	}
+	field, value, ok = strings.Cut(tok[1:len(tok)-1], "=")
+	return field, value, ok && field != ""
+}
+
+// selectElement returns the first element of the array whose child field
+// matches the value.
+func (r *Resolver) selectElement(doc reflect.Value, field, value string) (reflect.Value, error) {
+	for i := 0; i < doc.Len(); i++ {
+		elm := doc.Index(i)
+		childVal, err := r.getValue(elm, field)
+		if err != nil {
+			continue
+		}
+		if selectorMatches(indirect(childVal), value) {
+			return elm, nil
+		}
+	}
+	return reflect.Value{}, newError(ErrGet, "no array element matches selector '[%s=%s]'", field, value)
+}
+
+// selectorMatches reports whether the value equals the selector value.
+func selectorMatches(val reflect.Value, value string) bool {
+	switch val.Kind() {
+	case reflect.String:
+		return val.String() == value
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, err := strconv.ParseFloat(value, 64)
+		return err == nil && float64(val.Int()) == f
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, err := strconv.ParseFloat(value, 64)
+		return err == nil && float64(val.Uint()) == f
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		return err == nil && val.Float() == f
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		return err == nil && val.Bool() == b
+	case reflect.Invalid:
+		return false
+	}
+	return val.CanInterface() && fmt.Sprint(val.Interface()) == value
+}
@@ -0,0 +1,57 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestResolverMatchSelectors(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	var doc interface{}
+	json.Unmarshal([]byte(`{
+		"users": [
+			{"id": 7, "name": "alice", "role": "admin"},
+			{"id": 42, "name": "bob", "role": "user"},
+			{"name": "carol"}
+		]
+	}`), &doc)
+	typed := map[string][]user{
+		"users": {{ID: 1, Name: "dave"}, {ID: 2, Name: "eve"}},
+	}
+
+	ptr, _ := New("/users/[id=42]/name")
+	if _, err := ptr.Get(doc); err == nil {
+		t.Errorf("expected an error without MatchSelectors")
+	}
+
+	r := Resolver{MatchSelectors: true}
+	cases := []struct {
+		doc       interface{}
+		ptrstring string
+		expect    interface{}
+		err       string
+	}{
+		{doc, "/users/[id=42]/name", "bob", ""},
+		{doc, "/users/[id=7.0]/name", "alice", ""},
+		{doc, "/users/[name=carol]", map[string]interface{}{"name": "carol"}, ""},
+		{doc, "/users/[role=user]/id", float64(42), ""},
+		{doc, "/users/[id=1]", nil, "get: at /users: no array element matches selector '[id=1]'"},
+		{doc, "/users/1/name", "bob", ""},
+		{typed, "/users/[id=2]/name", "eve", ""},
+		{typed, "/users/[name=dave]/id", 1, ""},
+	}
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := r.Get(ptr, c.doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
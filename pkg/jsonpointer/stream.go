@@ -0,0 +1,38 @@
+package jsonpointer
+
+import "reflect"
+
+// StreamArray resolves the array that the pointer points to and calls fn for
+// each of its elements in order. If fn returns an error, the iteration stops
+// and the error is returned, so callers may stop early. It fails if the value
+// is not an array.
+func (p Pointer) StreamArray(doc interface{}, fn func(index int, value interface{}) error) error {
+	value, err := p.Get(doc)
+	if err != nil {
+		return err
+	}
+
+	if d, ok := value.(Indexable); ok && !isNil(reflect.ValueOf(d)) {
+		for i := 0; i < d.Len(); i++ {
+			if err := fn(i, d.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	arrVal := indirect(reflect.ValueOf(value))
+	if arrVal.Kind() != reflect.Array && arrVal.Kind() != reflect.Slice {
+		return newError(ErrGet, "value of type %T is not an array", value)
+	}
+	for i := 0; i < arrVal.Len(); i++ {
+		elmVal := arrVal.Index(i)
+		if !elmVal.CanInterface() {
+			return newError(ErrGet, "cannot get document value")
+		}
+		if err := fn(i, elmVal.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
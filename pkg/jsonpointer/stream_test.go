@@ -0,0 +1,51 @@
+package jsonpointer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStreamArray(t *testing.T) {
+	errStop := errors.New("stop")
+	doc := map[string]interface{}{
+		"items": []interface{}{"a", "b", "c", "d"},
+		"ints":  &[2]int{1, 2},
+		"obj":   map[string]interface{}{"a": 1},
+	}
+
+	cases := []struct {
+		ptrstring string
+		stopAt    int
+		expect    []interface{}
+		err       string
+	}{
+		{"/items", -1, []interface{}{"a", "b", "c", "d"}, ""},
+		{"/items", 1, []interface{}{"a", "b"}, "stop"},
+		{"/ints", -1, []interface{}{1, 2}, ""},
+		{"/obj", -1, nil, "get: value of type map[string]interface {} is not an array"},
+		{"/missing", -1, nil, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		var got []interface{}
+		err := ptr.StreamArray(doc, func(index int, value interface{}) error {
+			if index != len(got) {
+				t.Errorf("%s: expected index %d, got: %d", c.ptrstring, len(got), index)
+			}
+			got = append(got, value)
+			if index == c.stopAt {
+				return errStop
+			}
+			return nil
+		})
+		if c.stopAt >= 0 && !errors.Is(err, errStop) {
+			t.Errorf("%s: expected the error returned by fn, got: %v", c.ptrstring, err)
+		}
+		assertError(t, c.ptrstring, err, c.err)
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// Package textenc provides decoders of golang.org/x/text encodings for
+// jsonpointer.Dialect, so that pointer strings in legacy character encodings
+// can be parsed without adding the dependency to the jsonpointer package.
+package textenc
+
+import "golang.org/x/text/encoding"
+
+// Decode returns a function that converts text from the encoding to UTF-8,
+// for use as jsonpointer.Dialect.Decode.
+func Decode(enc encoding.Encoding) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		return enc.NewDecoder().Bytes(data)
+	}
+}
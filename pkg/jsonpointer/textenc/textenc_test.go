@@ -0,0 +1,29 @@
+package textenc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aisbergg/go-jsonpointer/pkg/jsonpointer"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestDecode(t *testing.T) {
+	doc := map[string]interface{}{
+		"café": map[string]interface{}{"crème/brûlée": 1},
+	}
+	// "/café/crème~1brûlée" encoded as Latin-1
+	raw := "/caf\xe9/cr\xe8me~1br\xfbl\xe9e"
+
+	d := jsonpointer.Dialect{Decode: Decode(charmap.ISO8859_1)}
+	ptr, err := d.New(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+	if expect := (jsonpointer.Pointer{"café", "crème/brûlée"}); !reflect.DeepEqual(ptr, expect) {
+		t.Errorf("expected: %#v, got: %#v", expect, ptr)
+	}
+	if got, err := ptr.Get(doc); err != nil || got != 1 {
+		t.Errorf("expected value 1, got: %#v (%v)", got, err)
+	}
+}
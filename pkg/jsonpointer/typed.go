@@ -0,0 +1,145 @@
+package jsonpointer
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GetDuration returns the value that the pointer points to as time.Duration.
+// See Resolver.GetDuration.
+func (p Pointer) GetDuration(doc interface{}) (time.Duration, error) {
+	return defaultResolver.GetDuration(p, doc)
+}
+
+// GetDuration returns the value that the pointer points to as time.Duration.
+// Strings are parsed with time.ParseDuration, e.g. "30s" or "1h30m". Numbers
+// are interpreted in the unit given by Resolver.DurationUnit, which defaults
+// to nanoseconds.
+func (r *Resolver) GetDuration(p Pointer, doc interface{}) (time.Duration, error) {
+	value, err := r.Get(p, doc)
+	if err != nil {
+		return 0, err
+	}
+	if d, ok := value.(time.Duration); ok {
+		return d, nil
+	}
+
+	unit := r.DurationUnit
+	if unit == 0 {
+		unit = time.Nanosecond
+	}
+	val := indirect(reflect.ValueOf(value))
+	switch val.Kind() {
+	case reflect.String:
+		d, err := time.ParseDuration(val.String())
+		if err != nil {
+			return 0, wrapError(err, ErrGet, "invalid duration '%s'", val.String())
+		}
+		return d, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(val.Int()) * unit, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(val.Uint()) * unit, nil
+	case reflect.Float32, reflect.Float64:
+		return time.Duration(val.Float() * float64(unit)), nil
+	}
+	return 0, newError(ErrGet, "cannot convert value of type %T to duration", value)
+}
+
+// GetString returns the value that the pointer points to as string. Values of
+// other types are converted like Set does, e.g. numbers are formatted.
+func (p Pointer) GetString(doc interface{}) (string, error) {
+	var s string
+	err := p.getConverted(doc, &s)
+	return s, err
+}
+
+// GetInt64 returns the value that the pointer points to as int64. Values of
+// other types are converted like Set does, e.g. floats are truncated and
+// strings are parsed.
+func (p Pointer) GetInt64(doc interface{}) (int64, error) {
+	var i int64
+	err := p.getConverted(doc, &i)
+	return i, err
+}
+
+// GetFloat64 returns the value that the pointer points to as float64. Values
+// of other types are converted like Set does, e.g. strings are parsed.
+func (p Pointer) GetFloat64(doc interface{}) (float64, error) {
+	var f float64
+	err := p.getConverted(doc, &f)
+	return f, err
+}
+
+// GetBool returns the value that the pointer points to as bool. Values of
+// other types are converted like Set does, e.g. non-zero numbers are true and
+// strings are parsed.
+func (p Pointer) GetBool(doc interface{}) (bool, error) {
+	var b bool
+	err := p.getConverted(doc, &b)
+	return b, err
+}
+
+// GetEnum returns the value that the pointer points to mapped through the
+// lookup table. The value must be a string that is contained in the table.
+func (p Pointer) GetEnum(doc interface{}, table map[string]int) (int, error) {
+	value, err := p.Get(doc)
+	if err != nil {
+		return 0, err
+	}
+	val := indirect(reflect.ValueOf(value))
+	if val.Kind() != reflect.String {
+		return 0, newError(ErrGet, "cannot convert value of type %T to enum", value)
+	}
+	e, ok := table[val.String()]
+	if !ok {
+		opts := make([]string, 0, len(table))
+		for opt := range table {
+			opts = append(opts, "'"+opt+"'")
+		}
+		sort.Strings(opts)
+		return 0, newError(ErrGet, "invalid enum value '%s', expected one of %s", val.String(), strings.Join(opts, ", "))
+	}
+	return e, nil
+}
+
+// getConverted resolves the pointer and stores the value in the variable that
+// target points to, converting it with the same logic as Set.
+func (p Pointer) getConverted(doc interface{}, target interface{}) error {
+	value, err := p.Get(doc)
+	if err != nil {
+		return err
+	}
+	targetVal := reflect.ValueOf(target).Elem()
+	if err := defaultResolver.setValue(targetVal, value); err != nil {
+		targetVal.Set(reflect.Zero(targetVal.Type()))
+		return wrapError(err, ErrGet, "cannot convert value of type %T to %s", value, targetVal.Type())
+	}
+	return nil
+}
+
+// GetTyped returns the value that the pointer points to as type T. Unlike the
+// typed getters of Pointer, the value is not converted, but type asserted. T
+// may also be an interface or pointer type. A null value yields the zero value
+// of T, if T can be nil.
+func GetTyped[T any](p Pointer, doc interface{}) (T, error) {
+	var zero T
+	value, err := p.Get(doc)
+	if err != nil {
+		return zero, err
+	}
+	typ := reflect.TypeOf(&zero).Elem()
+	if value == nil {
+		switch typ.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return zero, nil
+		}
+	}
+	t, ok := value.(T)
+	if !ok {
+		return zero, newError(ErrGet, "expected value of type %s, got %T", typ, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,202 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetDuration(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{
+		"timeout": "1m30s",
+		"interval": 1500,
+		"invalid": "soon",
+		"enabled": true
+	}`), &doc)
+
+	cases := []struct {
+		ptrstring string
+		unit      time.Duration
+		expect    time.Duration
+		err       string
+	}{
+		{"/timeout", 0, 90 * time.Second, ""},
+		{"/interval", 0, 1500 * time.Nanosecond, ""},
+		{"/interval", time.Millisecond, 1500 * time.Millisecond, ""},
+		{"/invalid", 0, 0, "get: invalid duration 'soon'"},
+		{"/enabled", 0, 0, "get: cannot convert value of type bool to duration"},
+		{"/missing", 0, 0, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		r := Resolver{DurationUnit: c.unit}
+		got, err := r.GetDuration(ptr, doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%s: expected: %s, got: %s", c.ptrstring, c.expect, got)
+		}
+	}
+
+	ptr, _ := New("/timeout")
+	if got, err := ptr.GetDuration(map[string]interface{}{"timeout": time.Second}); err != nil || got != time.Second {
+		t.Errorf("expected duration value to be returned as is, got: %s (%v)", got, err)
+	}
+}
+
+func TestTypedGetters(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{
+		"name": "foo",
+		"count": 42,
+		"ratio": 2.5,
+		"enabled": true,
+		"numeric": "17",
+		"list": [1]
+	}`), &doc)
+
+	getString := func(p Pointer) (interface{}, error) { return p.GetString(doc) }
+	getInt64 := func(p Pointer) (interface{}, error) { return p.GetInt64(doc) }
+	getFloat64 := func(p Pointer) (interface{}, error) { return p.GetFloat64(doc) }
+	getBool := func(p Pointer) (interface{}, error) { return p.GetBool(doc) }
+
+	cases := []struct {
+		ptrstring string
+		get       func(p Pointer) (interface{}, error)
+		expect    interface{}
+		err       string
+	}{
+		{"/name", getString, "foo", ""},
+		{"/count", getString, "42", ""},
+		{"/list", getString, "", "get: cannot convert value of type []interface {} to string"},
+		{"/count", getInt64, int64(42), ""},
+		{"/ratio", getInt64, int64(2), ""},
+		{"/numeric", getInt64, int64(17), ""},
+		{"/name", getInt64, int64(0), "get: cannot convert value of type string to int64"},
+		{"/ratio", getFloat64, 2.5, ""},
+		{"/numeric", getFloat64, 17.0, ""},
+		{"/missing", getFloat64, 0.0, "get: map has no key 'missing'"},
+		{"/enabled", getBool, true, ""},
+		{"/count", getBool, true, ""},
+		{"/name", getBool, false, "get: cannot convert value of type string to bool"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := c.get(ptr)
+		if got != c.expect {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+		if err != nil && errType(err) != ErrGet {
+			t.Errorf("%s: expected error of type %s, got: %s", c.ptrstring, ErrGet, errType(err))
+		}
+		assertError(t, c.ptrstring, err, c.err)
+	}
+}
+
+func TestGetTyped(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+	item := &Item{Name: "foo"}
+	doc := map[string]interface{}{
+		"struct": Item{Name: "bar"},
+		"ptr":    item,
+		"list":   []interface{}{1, "a"},
+		"err":    errors.New("failed"),
+		"null":   nil,
+	}
+
+	gotStruct, err := GetTyped[Item](Pointer{"struct"}, doc)
+	if err != nil {
+		t.Errorf("/struct: expected no error, got: %s", err)
+	}
+	if gotStruct != (Item{Name: "bar"}) {
+		t.Errorf("/struct: value mismatch, expected: %#v, got: %#v", Item{Name: "bar"}, gotStruct)
+	}
+
+	gotPtr, err := GetTyped[*Item](Pointer{"ptr"}, doc)
+	if err != nil {
+		t.Errorf("/ptr: expected no error, got: %s", err)
+	}
+	if gotPtr != item {
+		t.Errorf("/ptr: value mismatch, expected: %#v, got: %#v", item, gotPtr)
+	}
+
+	gotList, err := GetTyped[[]interface{}](Pointer{"list"}, doc)
+	if err != nil {
+		t.Errorf("/list: expected no error, got: %s", err)
+	}
+	if !reflect.DeepEqual(gotList, []interface{}{1, "a"}) {
+		t.Errorf("/list: value mismatch, expected: %#v, got: %#v", []interface{}{1, "a"}, gotList)
+	}
+
+	gotErr, err := GetTyped[error](Pointer{"err"}, doc)
+	if err != nil {
+		t.Errorf("/err: expected no error, got: %s", err)
+	}
+	if gotErr == nil || gotErr.Error() != "failed" {
+		t.Errorf("/err: value mismatch, got: %#v", gotErr)
+	}
+
+	gotNull, err := GetTyped[*Item](Pointer{"null"}, doc)
+	if err != nil {
+		t.Errorf("/null: expected no error, got: %s", err)
+	}
+	if gotNull != nil {
+		t.Errorf("/null: expected nil, got: %#v", gotNull)
+	}
+
+	gotMismatch, err := GetTyped[Item](Pointer{"ptr"}, doc)
+	assertError(t, "/ptr", err, "get: expected value of type jsonpointer.Item, got *jsonpointer.Item")
+	if gotMismatch != (Item{}) {
+		t.Errorf("/ptr: expected zero value, got: %#v", gotMismatch)
+	}
+
+	_, err = GetTyped[string](Pointer{"null"}, doc)
+	assertError(t, "/null", err, "get: expected value of type string, got <nil>")
+
+	_, err = GetTyped[string](Pointer{"missing"}, doc)
+	assertError(t, "/missing", err, "get: map has no key 'missing'")
+}
+
+func TestGetEnum(t *testing.T) {
+	const (
+		levelDebug = iota
+		levelInfo
+		levelError
+	)
+	table := map[string]int{"debug": levelDebug, "info": levelInfo, "error": levelError}
+	doc := map[string]interface{}{
+		"level":   "error",
+		"unknown": "trace",
+		"number":  1,
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    int
+		err       string
+	}{
+		{"/level", levelError, ""},
+		{"/unknown", 0, "get: invalid enum value 'trace', expected one of 'debug', 'error', 'info'"},
+		{"/number", 0, "get: cannot convert value of type int to enum"},
+		{"/missing", 0, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.GetEnum(doc, table)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
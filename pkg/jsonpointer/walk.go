@@ -0,0 +1,312 @@
+package jsonpointer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// SkipBranch can be returned by the function passed to Walk to skip the
+// children of the current value. It is not returned as an error by Walk.
+var SkipBranch = errors.New("skip this branch")
+
+// Walk walks the document, calling fn for each value in it, including the
+// document itself. Values are visited in depth-first order, the parent before
+// its children. Maps, slices, arrays and structs are descended into, with map
+// keys visited in sorted order and struct fields in declaration order. Struct
+// fields are addressed by their json tag name, if present; unexported fields
+// and fields tagged with "-" are skipped. If fn returns SkipBranch, the
+// children of the current value are skipped. If fn returns any other error,
+// the walk is aborted and the error is returned.
+func Walk(doc interface{}, fn func(p Pointer, value interface{}) error) error {
+	return defaultResolver.Walk(doc, fn)
+}
+
+// Walk walks the document like the package-level Walk, but addresses struct
+// fields by their Resolver.TagName tag. If Resolver.DedupeShared is set, values
+// that are shared between multiple locations of the document are only visited
+// once.
+func (r *Resolver) Walk(doc interface{}, fn func(p Pointer, value interface{}) error) error {
+	var seen map[nodeID]bool
+	if r.DedupeShared {
+		seen = map[nodeID]bool{}
+	}
+	return walk(Pointer{}, reflect.ValueOf(doc), fn, seen, r.tagName())
+}
+
+func walk(p Pointer, val reflect.Value, fn func(p Pointer, value interface{}) error, seen map[nodeID]bool, tag string) error {
+	if seen != nil {
+		if id, ok := nodeIdentity(val); ok {
+			if seen[id] {
+				return nil
+			}
+			seen[id] = true
+		}
+	}
+
+	var value interface{}
+	if val.IsValid() && val.CanInterface() {
+		value = val.Interface()
+	}
+	if err := fn(p, value); err != nil {
+		if errors.Is(err, SkipBranch) {
+			return nil
+		}
+		return err
+	}
+
+	toks, vals := children(val, tag)
+	for i, tok := range toks {
+		if err := walk(childPointer(p, tok), vals[i], fn, seen, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeID identifies a value that is referenced by a pointer, map or slice.
+type nodeID struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// nodeIdentity returns the identity of values that can be shared between
+// multiple locations of a document, i.e. non-nil pointers, maps and non-empty
+// slices.
+func nodeIdentity(val reflect.Value) (nodeID, bool) {
+	for val.Kind() == reflect.Interface && !val.IsNil() {
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Map:
+		if !val.IsNil() {
+			return nodeID{val.Type(), val.Pointer(), 0}, true
+		}
+	case reflect.Slice:
+		if val.Len() > 0 {
+			return nodeID{val.Type(), val.Pointer(), val.Len()}, true
+		}
+	}
+	return nodeID{}, false
+}
+
+// children returns the tokens and values of the direct children of a value.
+// Maps, slices, arrays and structs have children; map keys are returned in
+// sorted order and struct fields in declaration order, addressed by their tag
+// name for the given tag. Unexported fields and fields tagged with "-" are
+// skipped.
+func children(val reflect.Value, tag string) ([]string, []reflect.Value) {
+	val = indirect(val)
+	switch val.Kind() {
+	// -------------------------------------------------------------------------
+	// Array, Slice
+	// -------------------------------------------------------------------------
+	case reflect.Array, reflect.Slice:
+		toks := make([]string, val.Len())
+		vals := make([]reflect.Value, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			toks[i] = strconv.Itoa(i)
+			vals[i] = val.Index(i)
+		}
+		return toks, vals
+
+	// -------------------------------------------------------------------------
+	// Map
+	// -------------------------------------------------------------------------
+	case reflect.Map:
+		keys := val.MapKeys()
+		toks := make([]string, len(keys))
+		for i, key := range keys {
+			toks[i] = fmt.Sprint(key.Interface())
+		}
+		sort.Sort(byToken{toks, keys})
+		vals := make([]reflect.Value, len(keys))
+		for i, key := range keys {
+			vals[i] = val.MapIndex(key)
+		}
+		return toks, vals
+
+	// -------------------------------------------------------------------------
+	// Struct
+	// -------------------------------------------------------------------------
+	case reflect.Struct:
+		var (
+			toks []string
+			vals []reflect.Value
+		)
+		st := val.Type()
+		for i := 0; i < st.NumField(); i++ {
+			sf := st.Field(i)
+			if !sf.IsExported() || sf.Tag.Get(tag) == "-" {
+				continue
+			}
+			tok := tagName(sf, tag)
+			if tok == "" {
+				tok = sf.Name
+			}
+			toks = append(toks, tok)
+			vals = append(vals, val.Field(i))
+		}
+		return toks, vals
+	}
+	return nil, nil
+}
+
+// Children returns pointers to the direct children of the value that the
+// pointer points to. See Resolver.Children.
+func (p Pointer) Children(doc interface{}) ([]Pointer, error) {
+	return defaultResolver.Children(p, doc)
+}
+
+// Children returns pointers to the direct children of the value that the
+// pointer points to, in the same order as Walk visits them. If
+// Resolver.SparseArrays is set, maps with array index keys are ordered
+// numerically.
+func (r *Resolver) Children(p Pointer, doc interface{}) ([]Pointer, error) {
+	value, err := r.Get(p, doc)
+	if err != nil {
+		return nil, err
+	}
+	toks, _ := r.children(reflect.ValueOf(value))
+	ptrs := make([]Pointer, len(toks))
+	for i, tok := range toks {
+		ptrs[i] = childPointer(p, tok)
+	}
+	return ptrs, nil
+}
+
+// Siblings returns pointers to the children of the parent of the value that
+// the pointer points to, excluding the pointer itself. For the empty pointer,
+// an empty slice is returned.
+func (p Pointer) Siblings(doc interface{}) ([]Pointer, error) {
+	leaf, ok := p.Leaf()
+	if !ok {
+		return []Pointer{}, nil
+	}
+	ptrs, err := p.Parent().Children(doc)
+	if err != nil {
+		return nil, err
+	}
+	siblings := make([]Pointer, 0, len(ptrs))
+	for _, ptr := range ptrs {
+		if tok, _ := ptr.Leaf(); tok != leaf {
+			siblings = append(siblings, ptr)
+		}
+	}
+	return siblings, nil
+}
+
+// children is like the package-level children, but uses Resolver.TagName and
+// orders the children of maps with array index keys numerically if
+// Resolver.SparseArrays is set.
+func (r *Resolver) children(val reflect.Value) ([]string, []reflect.Value) {
+	toks, vals := children(val, r.tagName())
+	if r.SparseArrays && indirect(val).Kind() == reflect.Map {
+		sortByIndex(toks, vals)
+	}
+	return toks, vals
+}
+
+// sortByIndex sorts the tokens and their values numerically, if all tokens are
+// array indices. Otherwise, they are left unchanged.
+func sortByIndex(toks []string, vals []reflect.Value) {
+	idxs := make([]uint64, len(toks))
+	for i, tok := range toks {
+		idx, err := strconv.ParseUint(tok, 10, 64)
+		if err != nil {
+			return
+		}
+		idxs[i] = idx
+	}
+	sort.Sort(byIndex{idxs, toks, vals})
+}
+
+// byIndex sorts tokens and their values by their numeric indices.
+type byIndex struct {
+	idxs []uint64
+	toks []string
+	vals []reflect.Value
+}
+
+func (b byIndex) Len() int           { return len(b.idxs) }
+func (b byIndex) Less(i, j int) bool { return b.idxs[i] < b.idxs[j] }
+func (b byIndex) Swap(i, j int) {
+	b.idxs[i], b.idxs[j] = b.idxs[j], b.idxs[i]
+	b.toks[i], b.toks[j] = b.toks[j], b.toks[i]
+	b.vals[i], b.vals[j] = b.vals[j], b.vals[i]
+}
+
+// byToken sorts map keys by their tokens.
+type byToken struct {
+	toks []string
+	keys []reflect.Value
+}
+
+func (b byToken) Len() int           { return len(b.toks) }
+func (b byToken) Less(i, j int) bool { return b.toks[i] < b.toks[j] }
+func (b byToken) Swap(i, j int) {
+	b.toks[i], b.toks[j] = b.toks[j], b.toks[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// childPointer returns a new pointer with the token appended to p.
+func childPointer(p Pointer, tok string) Pointer {
+	newPtr := make(Pointer, len(p), len(p)+1)
+	copy(newPtr, p)
+	return append(newPtr, tok)
+}
+
+// NullPaths returns the pointers to all null values in the document, i.e. nil
+// interfaces and nil pointers. Empty maps, slices and arrays are not considered
+// null.
+func NullPaths(doc interface{}) []Pointer {
+	var paths []Pointer
+	Walk(doc, func(p Pointer, value interface{}) error {
+		if val := reflect.ValueOf(value); !val.IsValid() || val.Kind() == reflect.Pointer && val.IsNil() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	return paths
+}
+
+// ValueTypesUnder returns a histogram of the JSON types of all values below the
+// value that the pointer points to, excluding the value itself. The JSON types
+// are "object", "array", "string", "number", "boolean" and "null".
+func (p Pointer) ValueTypesUnder(doc interface{}) (map[string]int, error) {
+	value, err := p.Get(doc)
+	if err != nil {
+		return nil, err
+	}
+	hist := map[string]int{}
+	Walk(value, func(p Pointer, value interface{}) error {
+		if len(p) > 0 {
+			hist[jsonType(reflect.ValueOf(value))]++
+		}
+		return nil
+	})
+	return hist, nil
+}
+
+// jsonType returns the name of the JSON type that the value corresponds to.
+func jsonType(val reflect.Value) string {
+	val = indirect(val)
+	switch val.Kind() {
+	case reflect.Invalid:
+		return "null"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Array, reflect.Slice:
+		return "array"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return "unknown"
+}
@@ -0,0 +1,293 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	type item struct {
+		Name    string `json:"name"`
+		Comment string
+		Hidden  string `json:"-"`
+		private string
+	}
+	doc := map[string]interface{}{
+		"foo":   []interface{}{1, "two"},
+		"bar":   item{Name: "baz"},
+		"a/b":   map[string]int{"z": 1, "y": 2},
+		"empty": map[string]interface{}{},
+	}
+
+	var got []string
+	err := Walk(doc, func(p Pointer, value interface{}) error {
+		got = append(got, p.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+
+	expect := []string{
+		"",
+		"/a~1b",
+		"/a~1b/y",
+		"/a~1b/z",
+		"/bar",
+		"/bar/name",
+		"/bar/Comment",
+		"/empty",
+		"/foo",
+		"/foo/0",
+		"/foo/1",
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("walk order mismatch, expected: %v, got: %v", expect, got)
+	}
+
+	// every visited pointer resolves to the visited value
+	Walk(doc, func(p Pointer, value interface{}) error {
+		if got, err := p.Get(doc); err != nil || !reflect.DeepEqual(got, value) {
+			t.Errorf("%s: expected %#v, got: %#v (%v)", p, value, got, err)
+		}
+		return nil
+	})
+}
+
+func TestWalkSkipBranch(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": map[string]interface{}{"x": 1, "y": 2},
+		"b": []interface{}{1, []interface{}{2}},
+		"c": 3,
+	}
+
+	cases := []struct {
+		skip   string
+		expect []string
+	}{
+		{"/a", []string{"", "/a", "/b", "/b/0", "/b/1", "/b/1/0", "/c"}},
+		{"/b/1", []string{"", "/a", "/a/x", "/a/y", "/b", "/b/0", "/b/1", "/c"}},
+		{"/c", []string{"", "/a", "/a/x", "/a/y", "/b", "/b/0", "/b/1", "/b/1/0", "/c"}},
+		{"", []string{""}},
+	}
+
+	for _, c := range cases {
+		var got []string
+		err := Walk(doc, func(p Pointer, value interface{}) error {
+			got = append(got, p.String())
+			if p.String() == c.skip {
+				return SkipBranch
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.skip, err)
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: walk order mismatch, expected: %v, got: %v", c.skip, c.expect, got)
+		}
+	}
+
+	// other errors abort the walk
+	errAbort := errors.New("abort")
+	var got []string
+	err := Walk(doc, func(p Pointer, value interface{}) error {
+		got = append(got, p.String())
+		if p.String() == "/a/x" {
+			return errAbort
+		}
+		return nil
+	})
+	if err != errAbort {
+		t.Errorf("expected the error returned by fn, got: %v", err)
+	}
+	if expect := []string{"", "/a", "/a/x"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("walk order mismatch, expected: %v, got: %v", expect, got)
+	}
+}
+
+func TestNullPaths(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{
+		"a": null,
+		"b": {"c": null, "d": 1, "e": {}},
+		"f": [null, [], [null]],
+		"g": ""
+	}`), &doc)
+
+	got := NullPaths(doc)
+	expect := []Pointer{{"a"}, {"b", "c"}, {"f", "0"}, {"f", "2", "0"}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected: %v, got: %v", expect, got)
+	}
+
+	type document struct {
+		Ptr   *int           `json:"ptr"`
+		Map   map[string]int `json:"map"`
+		Slice []int          `json:"slice"`
+	}
+	got = NullPaths(document{Map: map[string]int{}, Slice: []int{}})
+	expect = []Pointer{{"ptr"}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected: %v, got: %v", expect, got)
+	}
+
+	if got := NullPaths(nil); !reflect.DeepEqual(got, []Pointer{{}}) {
+		t.Errorf("expected the root to be null, got: %v", got)
+	}
+}
+
+func TestValueTypesUnder(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{
+		"mixed": [1, "two", true, null, {"a": 1}, [2.5]],
+		"uniform": ["a", "b", "c"],
+		"scalar": 1
+	}`), &doc)
+
+	cases := []struct {
+		ptrstring string
+		expect    map[string]int
+		err       string
+	}{
+		{"/mixed", map[string]int{"number": 3, "string": 1, "boolean": 1, "null": 1, "object": 1, "array": 1}, ""},
+		{"/uniform", map[string]int{"string": 3}, ""},
+		{"/scalar", map[string]int{}, ""},
+		{"/missing", nil, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.ValueTypesUnder(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: expected: %v, got: %v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestResolverWalkDedupeShared(t *testing.T) {
+	type node struct {
+		Name string `json:"name"`
+	}
+	shared := map[string]interface{}{"x": 1}
+	sharedNode := &node{Name: "n"}
+	doc := map[string]interface{}{
+		"a":      shared,
+		"b":      shared,
+		"c":      map[string]interface{}{"x": 1},
+		"nodes":  []*node{sharedNode, sharedNode},
+		"values": []interface{}{0, 0},
+	}
+
+	collect := func(r *Resolver) []string {
+		var got []string
+		r.Walk(doc, func(p Pointer, value interface{}) error {
+			got = append(got, p.String())
+			return nil
+		})
+		return got
+	}
+
+	expect := []string{
+		"",
+		"/a", "/a/x",
+		"/b", "/b/x",
+		"/c", "/c/x",
+		"/nodes", "/nodes/0", "/nodes/0/name", "/nodes/1", "/nodes/1/name",
+		"/values", "/values/0", "/values/1",
+	}
+	if got := collect(&Resolver{}); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected: %v, got: %v", expect, got)
+	}
+
+	expect = []string{
+		"",
+		"/a", "/a/x",
+		"/c", "/c/x",
+		"/nodes", "/nodes/0", "/nodes/0/name",
+		"/values", "/values/0", "/values/1",
+	}
+	if got := collect(&Resolver{DedupeShared: true}); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected: %v, got: %v", expect, got)
+	}
+}
+
+func TestChildren(t *testing.T) {
+	type S struct {
+		A int `json:"a"`
+		B int
+	}
+	doc := map[string]interface{}{
+		"obj": map[string]interface{}{"b": 1, "a": 2},
+		"arr": []int{1, 2},
+		"s":   S{},
+		"x":   1,
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    []string
+		err       string
+	}{
+		{"/obj", []string{"/obj/a", "/obj/b"}, ""},
+		{"/arr", []string{"/arr/0", "/arr/1"}, ""},
+		{"/s", []string{"/s/a", "/s/B"}, ""},
+		{"/x", []string{}, ""},
+		{"/missing", nil, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		ptrs, err := ptr.Children(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		got := make([]string, len(ptrs))
+		for i, p := range ptrs {
+			got[i] = p.String()
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestSiblings(t *testing.T) {
+	doc := map[string]interface{}{
+		"obj": map[string]interface{}{"a": 1, "b": 2, "c": 3},
+		"arr": []interface{}{"x", "y", "z"},
+	}
+
+	cases := []struct {
+		ptrstring string
+		expect    []string
+		err       string
+	}{
+		{"/obj/b", []string{"/obj/a", "/obj/c"}, ""},
+		{"/arr/0", []string{"/arr/1", "/arr/2"}, ""},
+		{"/arr/2", []string{"/arr/0", "/arr/1"}, ""},
+		{"/obj", []string{"/arr"}, ""},
+		{"", []string{}, ""},
+		{"/missing/a", nil, "get: map has no key 'missing'"},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		ptrs, err := ptr.Siblings(doc)
+		if assertError(t, c.ptrstring, err, c.err) {
+			continue
+		}
+		got := make([]string, len(ptrs))
+		for i, p := range ptrs {
+			got[i] = p.String()
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: value mismatch, expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
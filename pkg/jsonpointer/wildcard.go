@@ -0,0 +1,133 @@
+package jsonpointer
+
+import (
+	"errors"
+	"reflect"
+)
+
+const (
+	// Wildcard is a token that matches every child of a value.
+	Wildcard = "*"
+
+	// RecursiveWildcard is a token that matches a value and all its
+	// descendants.
+	RecursiveWildcard = "**"
+)
+
+// ErrTooManyResults is returned by GetAll, wrapped in an error of type ErrGet,
+// if the expansion of wildcards yields more values than allowed by
+// Resolver.MaxResults.
+var ErrTooManyResults = errors.New("too many results")
+
+// Errors returned by CheckLimits, wrapped in an error of type
+// ErrInvalidJSONPointer.
+var (
+	ErrTooDeep          = errors.New("pointer too deep")
+	ErrTooManyWildcards = errors.New("too many wildcards")
+)
+
+// NoLimit can be passed to CheckLimits to not limit the depth or the number of
+// wildcards of a pointer.
+const NoLimit = -1
+
+// CheckLimits checks that the pointer has at most maxDepth tokens and at most
+// maxWildcards Wildcard or RecursiveWildcard tokens, e.g. before resolving
+// untrusted pointers with GetAll. A negative limit, such as NoLimit, means no
+// limit, so that a maxWildcards of zero rejects any wildcard. The returned
+// error matches ErrTooDeep or ErrTooManyWildcards with errors.Is.
+func (p Pointer) CheckLimits(maxDepth, maxWildcards int) error {
+	if maxDepth >= 0 && len(p) > maxDepth {
+		return wrapError(ErrTooDeep, ErrInvalidJSONPointer, "pointer depth of %d exceeds maximum of %d", len(p), maxDepth)
+	}
+	if maxWildcards >= 0 {
+		n := 0
+		for _, tok := range p {
+			if tok == Wildcard || tok == RecursiveWildcard {
+				n++
+			}
+		}
+		if n > maxWildcards {
+			return wrapError(ErrTooManyWildcards, ErrInvalidJSONPointer, "pointer has %d wildcards, exceeding maximum of %d", n, maxWildcards)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the concrete pointer matches the pattern pointer. A
+// Wildcard token "*" in the pattern matches any single token, all other tokens
+// must match exactly.
+func (pattern Pointer) Matches(concrete Pointer) bool {
+	if len(pattern) != len(concrete) {
+		return false
+	}
+	for i, tok := range pattern {
+		if tok != Wildcard && tok != concrete[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// GetAll returns all values from the given document that the pointer matches.
+// See Resolver.GetAll.
+func (p Pointer) GetAll(doc interface{}) ([]interface{}, error) {
+	return defaultResolver.GetAll(p, doc)
+}
+
+// GetAll returns all values from the given document that the pointer matches.
+// In addition to regular tokens, the pointer may contain the Wildcard token
+// "*", which matches every child of a value, and the RecursiveWildcard token
+// "**", which matches a value and all its descendants. Tokens that cannot be
+// resolved yield no values instead of an error. The values are returned in
+// document order, with map keys being sorted.
+//
+// If Resolver.MaxResults is set and the pointer matches more values, the
+// expansion stops and the truncated values are returned along with an error
+// matching ErrTooManyResults.
+func (r *Resolver) GetAll(p Pointer, doc interface{}) ([]interface{}, error) {
+	var results []interface{}
+	err := r.getAll(p, reflect.ValueOf(doc), &results)
+	return results, err
+}
+
+func (r *Resolver) getAll(p Pointer, val reflect.Value, results *[]interface{}) error {
+	if len(p) == 0 {
+		if !val.CanInterface() {
+			return nil
+		}
+		if r.MaxResults > 0 && len(*results) >= r.MaxResults {
+			return wrapError(ErrTooManyResults, ErrGet, "pointer matches more than %d values", r.MaxResults)
+		}
+		*results = append(*results, val.Interface())
+		return nil
+	}
+
+	switch p[0] {
+	case Wildcard:
+		_, vals := r.children(val)
+		for _, childVal := range vals {
+			if err := r.getAll(p[1:], childVal, results); err != nil {
+				return err
+			}
+		}
+
+	case RecursiveWildcard:
+		if err := r.getAll(p[1:], val, results); err != nil {
+			return err
+		}
+		_, vals := r.children(val)
+		for _, childVal := range vals {
+			if err := r.getAll(p, childVal, results); err != nil {
+				return err
+			}
+		}
+
+	default:
+		childVal, err := r.getValue(val, p[0])
+		if err != nil {
+			return nil
+		}
+		return r.getAll(p[1:], childVal, results)
+	}
+	return nil
+}
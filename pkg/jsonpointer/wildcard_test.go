@@ -0,0 +1,181 @@
+package jsonpointer
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetAll(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{
+		"items": [
+			{"name": "foo", "tags": ["a"]},
+			{"name": "bar"},
+			{"name": "baz", "tags": ["b", "c"]}
+		],
+		"meta": {"name": "qux"}
+	}`), &doc)
+
+	cases := []struct {
+		ptrstring string
+		expect    []interface{}
+	}{
+		{"/items/*/name", []interface{}{"foo", "bar", "baz"}},
+		{"/items/*/tags/*", []interface{}{"a", "b", "c"}},
+		{"/items/1/name", []interface{}{"bar"}},
+		{"/items/*/missing", nil},
+		{"/**/name", []interface{}{"foo", "bar", "baz", "qux"}},
+		{"/meta/**", []interface{}{map[string]interface{}{"name": "qux"}, "qux"}},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		got, err := ptr.GetAll(doc)
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.ptrstring, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+	}
+}
+
+func TestGetAllMaxResults(t *testing.T) {
+	items := make([]interface{}, 100)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": i}
+	}
+	doc := map[string]interface{}{"items": items}
+	ptr, _ := New("/**/*")
+
+	r := Resolver{MaxResults: 10}
+	got, err := r.GetAll(ptr, doc)
+	if !errors.Is(err, ErrTooManyResults) || !errors.Is(err, ErrGetFailed) {
+		t.Errorf("expected ErrTooManyResults, got: %v", err)
+	}
+	assertError(t, ptr.String(), err, "get: pointer matches more than 10 values")
+	if len(got) != 10 {
+		t.Errorf("expected 10 results, got: %d", len(got))
+	}
+
+	// the limit is not exceeded if the number of results equals the limit
+	ptr, _ = New("/items/*/id")
+	r = Resolver{MaxResults: 100}
+	got, err = r.GetAll(ptr, doc)
+	if err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+	if len(got) != 100 {
+		t.Errorf("expected 100 results, got: %d", len(got))
+	}
+}
+
+func TestMatches(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		concrete string
+		expect   bool
+	}{
+		{"/items/*/changed", "/items/0/changed", true},
+		{"/items/*/changed", "/items/foo/changed", true},
+		{"/items/*/changed", "/items/0/removed", false},
+		{"/items/*/changed", "/items/0/changed/now", false},
+		{"/items/*/changed", "/items/changed", false},
+		{"/*/0", "/items/0", true},
+		{"/*/0", "/items/1", false},
+		{"/items/*", "/items/", true},
+		{"/*/*", "/a/b", true},
+		{"/items", "/items", true},
+		{"", "", true},
+		{"", "/items", false},
+	}
+
+	for _, c := range cases {
+		pattern, _ := New(c.pattern)
+		concrete, _ := New(c.concrete)
+		if got := pattern.Matches(concrete); got != c.expect {
+			t.Errorf("%s, %s: expected: %t, got: %t", c.pattern, c.concrete, c.expect, got)
+		}
+	}
+}
+
+func TestResolverSparseArrays(t *testing.T) {
+	doc := map[string]interface{}{
+		"sparse": map[string]interface{}{"10": "c", "2": "b", "0": "a"},
+		"mixed":  map[string]interface{}{"10": "c", "2": "b", "x": "a"},
+	}
+
+	cases := []struct {
+		sparse    bool
+		ptrstring string
+		expect    []interface{}
+		children  []string
+	}{
+		{true, "/sparse/*", []interface{}{"a", "b", "c"}, []string{"/sparse/0", "/sparse/2", "/sparse/10"}},
+		{false, "/sparse/*", []interface{}{"a", "c", "b"}, []string{"/sparse/0", "/sparse/10", "/sparse/2"}},
+		{true, "/mixed/*", []interface{}{"c", "b", "a"}, []string{"/mixed/10", "/mixed/2", "/mixed/x"}},
+	}
+
+	for _, c := range cases {
+		r := Resolver{SparseArrays: c.sparse}
+		ptr, _ := New(c.ptrstring)
+		got, err := r.GetAll(ptr, doc)
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.ptrstring, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.expect) {
+			t.Errorf("%s: expected: %#v, got: %#v", c.ptrstring, c.expect, got)
+		}
+
+		ptrs, err := r.Children(ptr.Truncate(1), doc)
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.ptrstring, err)
+			continue
+		}
+		children := make([]string, len(ptrs))
+		for i, p := range ptrs {
+			children[i] = p.String()
+		}
+		if !reflect.DeepEqual(children, c.children) {
+			t.Errorf("%s: expected children: %v, got: %v", c.ptrstring, c.children, children)
+		}
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	cases := []struct {
+		ptrstring    string
+		maxDepth     int
+		maxWildcards int
+		sentinel     error
+		err          string
+	}{
+		{"/a/b/c", 3, NoLimit, nil, ""},
+		{"/a/b/c/d", 3, NoLimit, ErrTooDeep, "invalid pointer: pointer depth of 4 exceeds maximum of 3"},
+		{"/a/b/c/d", NoLimit, NoLimit, nil, ""},
+		{"/a/*/b/*", NoLimit, 2, nil, ""},
+		{"/a/*/b/**", NoLimit, 1, ErrTooManyWildcards, "invalid pointer: pointer has 2 wildcards, exceeding maximum of 1"},
+		{"/*/*/*", 5, 2, ErrTooManyWildcards, "invalid pointer: pointer has 3 wildcards, exceeding maximum of 2"},
+		{"/*/*/*/*", 3, 2, ErrTooDeep, "invalid pointer: pointer depth of 4 exceeds maximum of 3"},
+		{"/a~1*/b", NoLimit, 1, nil, ""},
+		{"/a/b", NoLimit, 0, nil, ""},
+		{"/a/*", NoLimit, 0, ErrTooManyWildcards, "invalid pointer: pointer has 1 wildcards, exceeding maximum of 0"},
+		{"/a/**", 5, 0, ErrTooManyWildcards, "invalid pointer: pointer has 1 wildcards, exceeding maximum of 0"},
+		{"", 0, 0, nil, ""},
+		{"/a", 0, NoLimit, ErrTooDeep, "invalid pointer: pointer depth of 1 exceeds maximum of 0"},
+		{"/a", -5, -5, nil, ""},
+	}
+
+	for _, c := range cases {
+		ptr, _ := New(c.ptrstring)
+		err := ptr.CheckLimits(c.maxDepth, c.maxWildcards)
+		if c.sentinel != nil && (!errors.Is(err, c.sentinel) || !errors.Is(err, ErrParseFailed)) {
+			t.Errorf("%s: expected error to match %q and %q", c.ptrstring, c.sentinel, ErrParseFailed)
+		}
+		assertError(t, c.ptrstring, err, c.err)
+	}
+}